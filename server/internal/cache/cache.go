@@ -0,0 +1,335 @@
+// Package cache keeps upstream provider responses in the cache table so
+// restarts and repeated scans don't hit the apis again
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vyxn/yuzu/internal/config"
+	"github.com/vyxn/yuzu/internal/pkg/req"
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+)
+
+// ConfigKey is where the cache settings are stored in the config table
+const ConfigKey = "cache"
+
+// pruneEvery is how many writes go by between prunes, pruning on every write
+// would rescan the whole table for each response
+const pruneEvery = 100
+
+// writes counts the responses stored since start, caches are loaded per
+// request so the count is shared by all of them
+var writes atomic.Int64
+
+// Duration is a time.Duration written as "24h" in json
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return yerr.WithStackf("decoding duration: %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return yerr.WithStackf("parsing duration <%s>: %w", s, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Settings bound the entries of a provider, zero values fall back to the
+// default settings
+type Settings struct {
+	// TTL is how long a response is served from the cache
+	TTL Duration `json:"ttl,omitempty"`
+	// MaxEntries drops the oldest responses of the provider beyond it
+	MaxEntries int `json:"maxEntries,omitempty"`
+	// Disabled skips the cache for the provider
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Config holds the default and per provider cache settings
+type Config struct {
+	Default   Settings            `json:"default"`
+	Providers map[string]Settings `json:"providers"`
+	// MaxBytes drops the oldest responses of every provider once the cache
+	// grows past it, 0 means no limit
+	MaxBytes int64 `json:"maxBytes"`
+}
+
+// DefaultConfig is used when the config table has no cache settings, the
+// rate limited apis keep their responses longer
+func DefaultConfig() Config {
+	week := Duration(7 * 24 * time.Hour)
+	return Config{
+		Default: Settings{
+			TTL:        Duration(24 * time.Hour),
+			MaxEntries: 10000,
+		},
+		Providers: map[string]Settings{
+			"comicvine": {TTL: week},
+			"metron":    {TTL: week},
+		},
+		MaxBytes: 256 << 20,
+	}
+}
+
+// Validate checks that no limit is negative
+func (c Config) Validate() error {
+	check := func(name string, s Settings) error {
+		if s.TTL < 0 || s.MaxEntries < 0 {
+			return yerr.WithStackf("negative cache limit for <%s>", name)
+		}
+		return nil
+	}
+	if err := check("default", c.Default); err != nil {
+		return err
+	}
+	for name, s := range c.Providers {
+		if err := check(name, s); err != nil {
+			return err
+		}
+	}
+	if c.MaxBytes < 0 {
+		return yerr.WithStackf("negative cache size <%d>", c.MaxBytes)
+	}
+	return nil
+}
+
+// Settings returns the settings of provider with the defaults filled in
+func (c Config) Settings(provider string) Settings {
+	s := c.Providers[provider]
+	if s.TTL == 0 {
+		s.TTL = c.Default.TTL
+	}
+	if s.MaxEntries == 0 {
+		s.MaxEntries = c.Default.MaxEntries
+	}
+	s.Disabled = s.Disabled || c.Default.Disabled || s.TTL == 0
+	return s
+}
+
+// Cache stores responses in db, it is safe for concurrent use since every
+// operation is a single statement
+type Cache struct {
+	db  *sqlx.DB
+	cfg Config
+}
+
+func New(db *sqlx.DB, cfg Config) *Cache {
+	return &Cache{db: db, cfg: cfg}
+}
+
+// Load builds a cache with the settings stored in the config table
+func Load(ctx context.Context, db *sqlx.DB) (*Cache, error) {
+	cfg, err := LoadConfig(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	return New(db, cfg), nil
+}
+
+// LoadConfig returns the settings stored in the config table, or the default
+// ones
+func LoadConfig(ctx context.Context, db *sqlx.DB) (Config, error) {
+	cfg := DefaultConfig()
+	err := config.Get(ctx, db, ConfigKey, &cfg)
+	return cfg, err
+}
+
+// For returns the cache of provider, requests use it through req.WithCache
+func (c *Cache) For(provider string) req.Cache {
+	return scope{c: c, provider: provider, settings: c.cfg.Settings(provider)}
+}
+
+// Stats describes the entries of a provider
+type Stats struct {
+	Provider string `db:"provider" json:"provider"`
+	Entries  int    `db:"entries"  json:"entries"`
+	Bytes    int64  `db:"bytes"    json:"bytes"`
+	Expired  int    `db:"expired"  json:"expired"`
+}
+
+// Stats counts the entries of every provider
+func (c *Cache) Stats(ctx context.Context) ([]Stats, error) {
+	out := []Stats{}
+	err := c.db.SelectContext(
+		ctx,
+		&out,
+		`SELECT provider, COUNT(*) AS entries, SUM(size) AS bytes,
+			SUM(expires_at <= CURRENT_TIMESTAMP) AS expired
+		FROM cache GROUP BY provider ORDER BY provider`,
+	)
+	if err != nil {
+		return nil, yerr.WithStackf("reading cache stats: %w", err)
+	}
+	return out, nil
+}
+
+// Invalidate drops the entries of provider whose key contains match, empty
+// values match everything, it returns the number of entries dropped
+func (c *Cache) Invalidate(
+	ctx context.Context,
+	provider, match string,
+) (int64, error) {
+	res, err := c.db.ExecContext(
+		ctx,
+		`DELETE FROM cache WHERE (? = '' OR provider = ?)
+		AND instr(key, ?) > 0`,
+		provider,
+		provider,
+		match,
+	)
+	if err != nil {
+		return 0, yerr.WithStackf(
+			"invalidating cache <%s> <%s>: %w",
+			provider,
+			match,
+			err,
+		)
+	}
+	return res.RowsAffected()
+}
+
+// Prune drops expired entries and the oldest ones beyond the limits of every
+// provider and the total size
+func (c *Cache) Prune(ctx context.Context) error {
+	_, err := c.db.ExecContext(
+		ctx,
+		"DELETE FROM cache WHERE expires_at <= CURRENT_TIMESTAMP",
+	)
+	if err != nil {
+		return yerr.WithStackf("pruning expired cache: %w", err)
+	}
+
+	providers := []string{}
+	err = c.db.SelectContext(
+		ctx,
+		&providers,
+		"SELECT DISTINCT provider FROM cache",
+	)
+	if err != nil {
+		return yerr.WithStackf("listing cached providers: %w", err)
+	}
+
+	for _, provider := range providers {
+		n := c.cfg.Settings(provider).MaxEntries
+		if n <= 0 {
+			continue
+		}
+		_, err = c.db.ExecContext(
+			ctx,
+			`DELETE FROM cache WHERE provider = ? AND rowid NOT IN (
+				SELECT rowid FROM cache WHERE provider = ?
+				ORDER BY created_at DESC, rowid DESC LIMIT ?
+			)`,
+			provider,
+			provider,
+			n,
+		)
+		if err != nil {
+			return yerr.WithStackf("pruning cache <%s>: %w", provider, err)
+		}
+	}
+
+	if c.cfg.MaxBytes > 0 {
+		_, err = c.db.ExecContext(
+			ctx,
+			`DELETE FROM cache WHERE rowid IN (
+				SELECT rowid FROM (
+					SELECT rowid, SUM(size) OVER (
+						ORDER BY created_at DESC, rowid DESC
+					) AS total FROM cache
+				) WHERE total > ?
+			)`,
+			c.cfg.MaxBytes,
+		)
+		if err != nil {
+			return yerr.WithStackf("pruning cache size: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scope is the cache of a single provider, errors are logged and count as
+// misses so a broken cache never fails a request
+type scope struct {
+	c        *Cache
+	provider string
+	settings Settings
+}
+
+func (s scope) Get(ctx context.Context, key string) ([]byte, bool) {
+	if s.settings.Disabled {
+		return nil, false
+	}
+
+	var data []byte
+	err := s.c.db.GetContext(
+		ctx,
+		&data,
+		`SELECT value FROM cache WHERE provider = ? AND key = ?
+		AND expires_at > CURRENT_TIMESTAMP`,
+		s.provider,
+		key,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.warn("reading cached response", key, err)
+	}
+	return data, err == nil
+}
+
+func (s scope) Set(ctx context.Context, key string, data []byte) {
+	if s.settings.Disabled {
+		return
+	}
+
+	ttl := time.Duration(s.settings.TTL)
+	_, err := s.c.db.ExecContext(
+		ctx,
+		`INSERT INTO cache (provider, key, value, size, created_at, expires_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP,
+			datetime('now', '+' || ? || ' seconds'))
+		ON CONFLICT (provider, key) DO UPDATE SET
+			value = excluded.value,
+			size = excluded.size,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at`,
+		s.provider,
+		key,
+		data,
+		len(data),
+		int64(ttl.Seconds()),
+	)
+	if err != nil {
+		s.warn("caching response", key, err)
+		return
+	}
+
+	if writes.Add(1)%pruneEvery == 0 {
+		if err := s.c.Prune(ctx); err != nil {
+			s.warn("pruning cache", key, err)
+		}
+	}
+}
+
+func (s scope) warn(msg, key string, err error) {
+	slog.Warn(
+		msg,
+		slog.String("provider", s.provider),
+		slog.String("key", key),
+		slog.Any("err", err),
+	)
+}
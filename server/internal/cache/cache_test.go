@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vyxn/yuzu/internal/pkg/dbtest"
+	"github.com/vyxn/yuzu/internal/pkg/req"
+)
+
+func newCache(t *testing.T, cfg Config) (*Cache, *sqlx.DB) {
+	db := dbtest.Open(t)
+	return New(db, cfg), db
+}
+
+func keys(t *testing.T, db *sqlx.DB, provider string) []string {
+	t.Helper()
+	out := []string{}
+	err := db.Select(
+		&out,
+		"SELECT key FROM cache WHERE provider = ? ORDER BY key",
+		provider,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestScopeTTL(t *testing.T) {
+	ctx := context.Background()
+	c, db := newCache(t, Config{Default: Settings{TTL: Duration(time.Hour)}})
+	s := c.For("kitsu")
+
+	s.Set(ctx, "a", []byte("data"))
+	if data, ok := s.Get(ctx, "a"); !ok || string(data) != "data" {
+		t.Fatalf("Get() = %q, %v, want the stored data", data, ok)
+	}
+
+	_, err := db.Exec(
+		"UPDATE cache SET expires_at = datetime('now', '-1 seconds')",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get(ctx, "a"); ok {
+		t.Error("Get() of an expired entry hit")
+	}
+}
+
+func TestScopeDisabled(t *testing.T) {
+	ctx := context.Background()
+	c, db := newCache(t, Config{
+		Default:   Settings{TTL: Duration(time.Hour)},
+		Providers: map[string]Settings{"kitsu": {Disabled: true}},
+	})
+
+	c.For("kitsu").Set(ctx, "a", []byte("data"))
+	if got := keys(t, db, "kitsu"); len(got) != 0 {
+		t.Errorf("disabled cache stored %q", got)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{
+			"max entries keeps the newest",
+			Config{Default: Settings{TTL: Duration(time.Hour), MaxEntries: 2}},
+			[]string{"b", "c"},
+		},
+		{
+			"max bytes keeps the newest",
+			Config{Default: Settings{TTL: Duration(time.Hour)}, MaxBytes: 9},
+			[]string{"b", "c"},
+		},
+		{
+			"no limits",
+			Config{Default: Settings{TTL: Duration(time.Hour)}},
+			[]string{"a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, db := newCache(t, tt.cfg)
+			s := c.For("kitsu")
+			for _, k := range []string{"a", "b", "c"} {
+				s.Set(ctx, k, []byte("data"))
+			}
+
+			if err := c.Prune(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if got := keys(t, db, "kitsu"); !slices.Equal(got, tt.want) {
+				t.Errorf("keys after Prune() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetPrunes(t *testing.T) {
+	ctx := context.Background()
+	c, db := newCache(t, Config{
+		Default: Settings{TTL: Duration(time.Hour), MaxEntries: 10},
+	})
+	s := c.For("kitsu")
+
+	writes.Store(0)
+	for i := range pruneEvery - 1 {
+		s.Set(ctx, fmt.Sprint(i), []byte("data"))
+	}
+	if got := len(keys(t, db, "kitsu")); got != pruneEvery-1 {
+		t.Fatalf("%d entries before the prune, want %d", got, pruneEvery-1)
+	}
+
+	s.Set(ctx, "last", []byte("data"))
+	if got := len(keys(t, db, "kitsu")); got != 10 {
+		t.Errorf("%d entries after the prune, want 10", got)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c, db := newCache(t, Config{Default: Settings{TTL: Duration(time.Hour)}})
+	c.For("kitsu").Set(ctx, "GET /manga?filter=Berserk", []byte("data"))
+	c.For("kitsu").Set(ctx, "GET /manga?filter=Vagabond", []byte("data"))
+	c.For("anilist").Set(ctx, "POST /Berserk", []byte("data"))
+
+	n, err := c.Invalidate(ctx, "kitsu", "Berserk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("Invalidate() = %d, want 1", n)
+	}
+	want := []string{"GET /manga?filter=Vagabond"}
+	if got := keys(t, db, "kitsu"); !slices.Equal(got, want) {
+		t.Errorf("kitsu keys = %q, want %q", got, want)
+	}
+	if got := keys(t, db, "anilist"); len(got) != 1 {
+		t.Errorf("anilist keys = %q, want them untouched", got)
+	}
+}
+
+func TestValidateFailureNotCached(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"result":"error"}`))
+		},
+	))
+	t.Cleanup(srv.Close)
+
+	c, db := newCache(t, Config{Default: Settings{TTL: Duration(time.Hour)}})
+	client := req.NewClient(srv.URL, srv.Client())
+	errBody := errors.New("error body")
+	client.Validate = func([]byte) error { return errBody }
+
+	ctx := req.WithCache(context.Background(), c.For("mangadex"))
+	if _, err := client.Get(ctx, "/manga", nil); !errors.Is(err, errBody) {
+		t.Fatalf("Get() error = %v, want the validation error", err)
+	}
+	if got := keys(t, db, "mangadex"); len(got) != 0 {
+		t.Errorf("rejected response cached under %q", got)
+	}
+
+	client.Validate = nil
+	if _, err := client.Get(ctx, "/manga", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := keys(t, db, "mangadex"); len(got) != 1 {
+		t.Errorf("valid response cached under %q, want one key", got)
+	}
+}
@@ -0,0 +1,77 @@
+// Package config reads and writes the json document kept in the config table
+package config
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+)
+
+// Get decodes the value stored under key into v, v is left untouched when the
+// key isn't set so it can be prefilled with defaults
+func Get(ctx context.Context, db *sqlx.DB, key string, v any) error {
+	var raw sql.NullString
+	err := db.GetContext(
+		ctx,
+		&raw,
+		"SELECT config -> ('$.' || ?) FROM config LIMIT 1",
+		key,
+	)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !raw.Valid) {
+		return nil
+	}
+	if err != nil {
+		return yerr.WithStackf("reading config <%s>: %w", key, err)
+	}
+
+	if err := json.Unmarshal([]byte(raw.String), v); err != nil {
+		return yerr.WithStackf("decoding config <%s>: %w", key, err)
+	}
+
+	return nil
+}
+
+// Set stores v as json under key
+func Set(ctx context.Context, db *sqlx.DB, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return yerr.WithStackf("encoding config <%s>: %w", key, err)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return yerr.WithStackf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		"UPDATE config SET config = json_set(config, '$.' || ?, json(?))",
+		key,
+		string(data),
+	)
+	if err != nil {
+		return yerr.WithStackf("writing config <%s>: %w", key, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO config (config) VALUES (json_set('{}', '$.' || ?, json(?)))",
+			key,
+			string(data),
+		)
+		if err != nil {
+			return yerr.WithStackf("writing config <%s>: %w", key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return yerr.WithStackf("committing config <%s>: %w", key, err)
+	}
+
+	return nil
+}
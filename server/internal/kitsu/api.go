@@ -1,87 +1,80 @@
 package kitsu
 
 import (
-	"crypto/tls"
-	"fmt"
-	"io"
-	"log/slog"
-	"net/http"
+	"context"
 	"net/url"
 	"path"
-	"time"
 
-	"github.com/vyxn/yuzu/internal/pkg/log"
+	"github.com/vyxn/yuzu/internal/pkg/req"
+	"github.com/vyxn/yuzu/internal/provider"
 )
 
-var logger *slog.Logger
+// DefaultBaseURL is the api used by DefaultClient
+const DefaultBaseURL = "https://kitsu.io/api/edge"
 
-func init() {
-	logger = log.NewLogger()
-}
-
-func GetURL(url string) []byte {
-	logger.Info("→ r",
-		slog.String("url", url),
-		slog.String("method", http.MethodGet),
-	)
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // ⚠️ unsafe
-	}
-	client := &http.Client{Timeout: 10 * time.Second, Transport: tr}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		panic(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(resp.Body)
-		panic(fmt.Errorf("bad status %s: %s", resp.Status, string(b)))
-	}
+// DefaultClient is used by the registered provider and the debug routes
+var DefaultClient = req.NewClient(DefaultBaseURL, nil)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		panic(err)
-	}
+// mangaIncludes are the relationships fetched along with a manga, in the
+// included array of the response
+const mangaIncludes = "genres,categories,staff.person,characters.character"
 
-	return body
+// GetURL fetches an api path or an absolute url found in responses
+func GetURL(ctx context.Context, client *req.Client, url string) ([]byte, error) {
+	return client.Get(ctx, url, nil)
 }
 
-func GetSearchByName(name string) []byte {
-	baseURL, err := url.Parse("https://kitsu.io/api/edge/manga")
-	if err != nil {
-		panic(err)
-	}
-
+func GetSearchByName(
+	ctx context.Context,
+	client *req.Client,
+	name string,
+) ([]byte, error) {
 	params := url.Values{}
 	params.Add("filter[text]", name)
-	baseURL.RawQuery = params.Encode()
 
-	return GetURL(baseURL.String())
+	return GetURL(ctx, client, "/manga?"+params.Encode())
 }
 
-func GetMangaAllChaptersInfo(mangaID string) []byte {
-	baseURL, err := url.Parse("https://kitsu.io/api/edge/chapters")
-	if err != nil {
-		panic(err)
+func GetMangaByID(
+	ctx context.Context,
+	client *req.Client,
+	mangaID string,
+) ([]byte, error) {
+	if err := provider.CheckNumericID(mangaID); err != nil {
+		return nil, err
 	}
+	params := url.Values{}
+	params.Add("include", mangaIncludes)
 
-	baseURL.Path = path.Join(baseURL.Path, mangaID)
-	return GetURL(baseURL.String())
+	return GetURL(ctx, client, path.Join("/manga", mangaID)+"?"+params.Encode())
 }
 
-func GetMangaChapterInfo(mangaID string, chapter string) []byte {
-	baseURL, err := url.Parse("https://kitsu.io/api/edge/manga")
-	if err != nil {
-		panic(err)
+func GetMangaAllChaptersInfo(
+	ctx context.Context,
+	client *req.Client,
+	mangaID string,
+) ([]byte, error) {
+	if err := provider.CheckNumericID(mangaID); err != nil {
+		return nil, err
 	}
+	return GetURL(ctx, client, path.Join("/chapters", mangaID))
+}
 
-	baseURL.Path = path.Join(baseURL.Path, mangaID, "chapters")
+func GetMangaChapterInfo(
+	ctx context.Context,
+	client *req.Client,
+	mangaID string,
+	chapter string,
+) ([]byte, error) {
+	if err := provider.CheckNumericID(mangaID); err != nil {
+		return nil, err
+	}
 	params := url.Values{}
 	params.Add("filter[number]", chapter)
-	baseURL.RawQuery = params.Encode()
 
-	return GetURL(baseURL.String())
+	return GetURL(
+		ctx,
+		client,
+		path.Join("/manga", mangaID, "chapters")+"?"+params.Encode(),
+	)
 }
@@ -1,12 +1,16 @@
 package kitsu
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
-	"log/slog"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
 	"github.com/vyxn/yuzu/internal/standard"
 )
 
@@ -17,73 +21,115 @@ type Links struct {
 	} `json:"links"`
 }
 
+type MangaData struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Links struct {
+		Self string `json:"self"`
+	} `json:"links"`
+	Attributes struct {
+		CreatedAt           time.Time         `json:"createdAt"`
+		UpdatedAt           time.Time         `json:"updatedAt"`
+		Slug                string            `json:"slug"`
+		Synopsis            string            `json:"synopsis"`
+		Description         string            `json:"description"`
+		CoverImageTopOffset int               `json:"coverImageTopOffset"`
+		Titles              map[string]string `json:"titles"`
+		CanonicalTitle      string            `json:"canonicalTitle"`
+		AbbreviatedTitles   []string          `json:"abbreviatedTitles"`
+		AverageRating       string            `json:"averageRating"`
+		UserCount           int               `json:"userCount"`
+		FavoritesCount      int               `json:"favoritesCount"`
+		StartDate           string            `json:"startDate"`
+		EndDate             string            `json:"endDate"`
+		NextRelease         any               `json:"nextRelease"`
+		PopularityRank      int               `json:"popularityRank"`
+		RatingRank          int               `json:"ratingRank"`
+		AgeRating           string            `json:"ageRating"`
+		AgeRatingGuide      any               `json:"ageRatingGuide"`
+		Subtype             string            `json:"subtype"`
+		Status              string            `json:"status"`
+		Tba                 any               `json:"tba"`
+		PosterImage         struct {
+			Original string `json:"original"`
+		} `json:"posterImage"`
+		CoverImage struct {
+			Original string `json:"original"`
+		} `json:"coverImage"`
+		ChapterCount  int    `json:"chapterCount"`
+		VolumeCount   int    `json:"volumeCount"`
+		Serialization any    `json:"serialization"`
+		MangaType     string `json:"mangaType"`
+	} `json:"attributes"`
+	Relationships struct {
+		Genres             Links `json:"genres"`
+		Categories         Links `json:"categories"`
+		Castings           Links `json:"castings"`
+		Installments       Links `json:"installments"`
+		Mappings           Links `json:"mappings"`
+		Reviews            Links `json:"reviews"`
+		MediaRelationships Links `json:"mediaRelationships"`
+		Characters         Links `json:"characters"`
+		Staff              Links `json:"staff"`
+		Productions        Links `json:"productions"`
+		Quotes             Links `json:"quotes"`
+		Chapters           Links `json:"chapters"`
+		MangaCharacters    Links `json:"mangaCharacters"`
+		MangaStaff         Links `json:"mangaStaff"`
+	} `json:"relationships"`
+}
+
 type MangaInfo struct {
-	Data struct {
-		ID    string `json:"id"`
-		Type  string `json:"type"`
-		Links struct {
-			Self string `json:"self"`
-		} `json:"links"`
-		Attributes struct {
-			CreatedAt           time.Time         `json:"createdAt"`
-			UpdatedAt           time.Time         `json:"updatedAt"`
-			Slug                string            `json:"slug"`
-			Synopsis            string            `json:"synopsis"`
-			Description         string            `json:"description"`
-			CoverImageTopOffset int               `json:"coverImageTopOffset"`
-			Titles              map[string]string `json:"titles"`
-			CanonicalTitle      string            `json:"canonicalTitle"`
-			AbbreviatedTitles   []string          `json:"abbreviatedTitles"`
-			AverageRating       string            `json:"averageRating"`
-			UserCount           int               `json:"userCount"`
-			FavoritesCount      int               `json:"favoritesCount"`
-			StartDate           string            `json:"startDate"`
-			EndDate             string            `json:"endDate"`
-			NextRelease         any               `json:"nextRelease"`
-			PopularityRank      int               `json:"popularityRank"`
-			RatingRank          int               `json:"ratingRank"`
-			AgeRating           string            `json:"ageRating"`
-			AgeRatingGuide      any               `json:"ageRatingGuide"`
-			Subtype             string            `json:"subtype"`
-			Status              string            `json:"status"`
-			Tba                 any               `json:"tba"`
-			PosterImage         struct {
-				Original string `json:"original"`
-			} `json:"posterImage"`
-			CoverImage struct {
-				Original string `json:"original"`
-			} `json:"coverImage"`
-			ChapterCount  int    `json:"chapterCount"`
-			VolumeCount   int    `json:"volumeCount"`
-			Serialization any    `json:"serialization"`
-			MangaType     string `json:"mangaType"`
-		} `json:"attributes"`
-		Relationships struct {
-			Genres             Links `json:"genres"`
-			Categories         Links `json:"categories"`
-			Castings           Links `json:"castings"`
-			Installments       Links `json:"installments"`
-			Mappings           Links `json:"mappings"`
-			Reviews            Links `json:"reviews"`
-			MediaRelationships Links `json:"mediaRelationships"`
-			Characters         Links `json:"characters"`
-			Staff              Links `json:"staff"`
-			Productions        Links `json:"productions"`
-			Quotes             Links `json:"quotes"`
-			Chapters           Links `json:"chapters"`
-			MangaCharacters    Links `json:"mangaCharacters"`
-			MangaStaff         Links `json:"mangaStaff"`
-		} `json:"relationships"`
+	Data MangaData `json:"data"`
+	// Included holds the relationships asked for with include=
+	Included []Resource `json:"included"`
+}
+
+// Relationship points at a single resource, Data is nil when there is none
+type Relationship struct {
+	Data *struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
 	} `json:"data"`
 }
 
-func ParseMangaInfo(data []byte) MangaInfo {
+// Resource is an entry of the included array, the attributes of genres,
+// categories, staff, people and characters share the struct
+type Resource struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Name          string `json:"name"`
+		Title         string `json:"title"`
+		CanonicalName string `json:"canonicalName"`
+		Role          string `json:"role"`
+	} `json:"attributes"`
+	Relationships struct {
+		Person    Relationship `json:"person"`
+		Character Relationship `json:"character"`
+	} `json:"relationships"`
+}
+
+type MangaList struct {
+	Data []MangaData `json:"data"`
+}
+
+func ParseMangaInfo(data []byte) (MangaInfo, error) {
 	var mangaInfo MangaInfo
 	if err := json.Unmarshal(data, &mangaInfo); err != nil {
-		panic(err)
+		return mangaInfo, yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+
+	return mangaInfo, nil
+}
+
+func ParseMangaList(data []byte) (MangaList, error) {
+	var list MangaList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return list, yerr.WithStackf("unmarshaling json response: %w", err)
 	}
 
-	return mangaInfo
+	return list, nil
 }
 
 type MangaChapter struct {
@@ -104,16 +150,18 @@ type MangaChapter struct {
 	} `json:"data"`
 }
 
-func ParseMangaChapter(data []byte) MangaChapter {
+func ParseMangaChapter(data []byte) (MangaChapter, error) {
 	var chapter MangaChapter
 	if err := json.Unmarshal(data, &chapter); err != nil {
-		panic(err)
+		return chapter, yerr.WithStackf("unmarshaling json response: %w", err)
 	}
 
-	return chapter
+	return chapter, nil
 }
 
-func ParseMangaListSelfLink(data []byte) string {
+// ParseMangaListSelfLink returns the link of the first manga in a search
+// result
+func ParseMangaListSelfLink(data []byte) (string, error) {
 	type Links struct {
 		Self string `json:"self"`
 	}
@@ -126,24 +174,26 @@ func ParseMangaListSelfLink(data []byte) string {
 
 	var res Response
 	if err := json.Unmarshal(data, &res); err != nil {
-		logger.Error(
-			"error Unmarshalling response",
-			slog.String("data", string(data)),
-		)
-		panic(err)
+		return "", yerr.WithStackf("unmarshaling json response: %w", err)
 	}
-
-	for _, d := range res.Data {
-		return d.Links.Self
+	if len(res.Data) == 0 {
+		return "", yerr.WithStackf("empty manga list: %w", provider.ErrNotFound)
 	}
 
-	return ""
+	return res.Data[0].Links.Self, nil
 }
 
 func ParseToComicInfoChapter(
 	seriesData MangaInfo,
 	chapterData MangaChapter,
 ) (*standard.ComicInfoChapter, error) {
+	if len(chapterData.Data) == 0 {
+		return nil, yerr.WithStackf(
+			"no chapter for manga <%s>: %w",
+			seriesData.Data.ID,
+			provider.ErrNotFound,
+		)
+	}
 	manga := seriesData.Data.Attributes
 	chapter := chapterData.Data[0]
 
@@ -157,15 +207,29 @@ func ParseToComicInfoChapter(
 			chapter.Attributes.Number,
 			chapter.Attributes.CanonicalTitle,
 		),
-		Series:          manga.CanonicalTitle,
 		Number:          strconv.Itoa(chapter.Attributes.Number),
 		Summary:         manga.Synopsis,
 		Notes:           "Autogenerated with yuzu 🍋",
 		Manga:           "YesAndRightToLeft",
 		AgeRating:       manga.AgeRating,
 		CommunityRating: rating * 5 / 100,
+		ProviderID:      seriesData.Data.ID,
 	}
 
+	standard.GetTitlePreference().Apply(ci, Titles(seriesData))
+	ci.Genre, ci.Tags = genres(seriesData)
+	credits := map[string]*standard.List{
+		"Writer":      &ci.Writer,
+		"Penciller":   &ci.Penciller,
+		"CoverArtist": &ci.CoverArtist,
+	}
+	for _, c := range staff(seriesData) {
+		for _, field := range roles[strings.ToLower(c.role)] {
+			*credits[field] = credits[field].Add(c.name)
+		}
+	}
+	ci.Characters = characters(seriesData)
+
 	if chapter.Attributes.VolumeNumber != 0 {
 		ci.Volume = chapter.Attributes.VolumeNumber
 	}
@@ -175,3 +239,196 @@ func ParseToComicInfoChapter(
 
 	return ci, nil
 }
+
+// statuses maps kitsu's manga status to the standard series status
+var statuses = map[string]string{
+	"current":    standard.SeriesStatusOngoing,
+	"finished":   standard.SeriesStatusEnded,
+	"tba":        standard.SeriesStatusUnknown,
+	"unreleased": standard.SeriesStatusUnknown,
+	"upcoming":   standard.SeriesStatusUnknown,
+}
+
+func ParseToComicInfoSeries(
+	seriesData MangaInfo,
+) (*standard.ComicInfoSeries, error) {
+	manga := seriesData.Data.Attributes
+
+	rating, _ := strconv.ParseFloat(manga.AverageRating, 64)
+
+	cs := &standard.ComicInfoSeries{
+		Summary:         manga.Synopsis,
+		Status:          statuses[manga.Status],
+		Count:           manga.ChapterCount,
+		VolumeCount:     manga.VolumeCount,
+		AgeRating:       manga.AgeRating,
+		Manga:           "YesAndRightToLeft",
+		CommunityRating: rating * 5 / 100,
+		CoverURL:        manga.PosterImage.Original,
+		Web:             "https://kitsu.io/manga/" + manga.Slug,
+		ProviderID:      seriesData.Data.ID,
+	}
+
+	standard.GetTitlePreference().ApplySeries(cs, Titles(seriesData))
+	cs.Genre, cs.Tags = genres(seriesData)
+
+	if start, err := time.Parse(time.DateOnly, manga.StartDate); err == nil {
+		cs.Year = start.Year()
+	}
+
+	return cs, nil
+}
+
+// roles maps kitsu staff roles to the ComicInfo credit fields they fill,
+// manga artists draw their own covers
+var roles = map[string][]string{
+	"story & art":      {"Writer", "Penciller", "CoverArtist"},
+	"story":            {"Writer"},
+	"original creator": {"Writer"},
+	"art":              {"Penciller", "CoverArtist"},
+}
+
+type credit struct {
+	name, role string
+}
+
+// included indexes the included resources by type and id
+func included(seriesData MangaInfo) map[[2]string]Resource {
+	out := make(map[[2]string]Resource, len(seriesData.Included))
+	for _, r := range seriesData.Included {
+		out[[2]string{r.Type, r.ID}] = r
+	}
+	return out
+}
+
+// related returns the resource rel points at
+func related(index map[[2]string]Resource, rel Relationship) (Resource, bool) {
+	if rel.Data == nil {
+		return Resource{}, false
+	}
+	r, ok := index[[2]string{rel.Data.Type, rel.Data.ID}]
+	return r, ok
+}
+
+// staff lists the people credited on the manga with their role
+func staff(seriesData MangaInfo) []credit {
+	index := included(seriesData)
+	out := []credit{}
+	for _, r := range seriesData.Included {
+		if r.Type != "mediaStaff" {
+			continue
+		}
+		if p, ok := related(index, r.Relationships.Person); ok {
+			out = append(out, credit{p.Attributes.Name, r.Attributes.Role})
+		}
+	}
+	return out
+}
+
+// characters lists the characters of the manga, main ones first
+func characters(seriesData MangaInfo) standard.List {
+	index := included(seriesData)
+	var main, others standard.List
+	for _, r := range seriesData.Included {
+		if r.Type != "mediaCharacters" {
+			continue
+		}
+		c, ok := related(index, r.Relationships.Character)
+		if !ok {
+			continue
+		}
+		name := cmp.Or(c.Attributes.CanonicalName, c.Attributes.Name)
+		if r.Attributes.Role == "main" {
+			main = main.Add(name)
+		} else {
+			others = others.Add(name)
+		}
+	}
+	return main.Union(others)
+}
+
+// genres returns the genres and the categories of the manga, kitsu replaced
+// genres with categories so they stand in for the genres when there are none
+func genres(seriesData MangaInfo) (genre, tags standard.List) {
+	for _, r := range seriesData.Included {
+		switch r.Type {
+		case "genres":
+			genre = genre.Add(r.Attributes.Name)
+		case "categories":
+			tags = tags.Add(r.Attributes.Title)
+		}
+	}
+	if len(genre) == 0 {
+		genre = tags
+	}
+	return genre, tags
+}
+
+// titleLocales maps the country part of kitsu's title keys to a language
+var titleLocales = map[string]string{
+	"jp": "ja",
+	"kr": "ko",
+	"cn": "zh",
+	"tw": "zh",
+	"th": "th",
+}
+
+// titleLanguage converts a kitsu title key such as "en_jp" to a BCP 47 tag,
+// kitsu uses "en_<country>" for romanizations
+func titleLanguage(key string) string {
+	lang, country, _ := strings.Cut(key, "_")
+	if lang == "en" && country != "" && country != "us" && country != "gb" {
+		if l, ok := titleLocales[country]; ok {
+			return l + "-Latn"
+		}
+	}
+	return lang
+}
+
+// Titles lists every title of the manga, canonical first
+func Titles(seriesData MangaInfo) []standard.Title {
+	return titles(seriesData.Data)
+}
+
+func titles(data MangaData) []standard.Title {
+	manga := data.Attributes
+
+	keys := make([]string, 0, len(manga.Titles))
+	for k := range manga.Titles {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	canonical := standard.Title{Value: manga.CanonicalTitle}
+	titles := []standard.Title{}
+	for _, k := range keys {
+		t := standard.Title{Lang: titleLanguage(k), Value: manga.Titles[k]}
+		if t.Value == manga.CanonicalTitle {
+			canonical.Lang = t.Lang
+		}
+		titles = append(titles, t)
+	}
+	for _, a := range manga.AbbreviatedTitles {
+		titles = append(titles, standard.Title{Value: a})
+	}
+
+	return append([]standard.Title{canonical}, titles...)
+}
+
+// ParseSearchCandidates converts a manga search result to provider candidates
+func ParseSearchCandidates(list MangaList) []provider.SeriesCandidate {
+	out := make([]provider.SeriesCandidate, 0, len(list.Data))
+	for _, m := range list.Data {
+		c := provider.SeriesCandidate{
+			ProviderID: m.ID,
+			Titles:     titles(m),
+			Type:       m.Attributes.Subtype,
+			CoverURL:   m.Attributes.PosterImage.Original,
+		}
+		if start, err := time.Parse(time.DateOnly, m.Attributes.StartDate); err == nil {
+			c.Year = start.Year()
+		}
+		out = append(out, c)
+	}
+	return out
+}
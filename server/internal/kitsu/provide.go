@@ -4,30 +4,129 @@ package kitsu
 import (
 	"context"
 
+	"github.com/vyxn/yuzu/internal/pkg/req"
+	"github.com/vyxn/yuzu/internal/provider"
 	"github.com/vyxn/yuzu/internal/standard"
 )
 
+// KitsuComicInfoProvider keeps no state, responses are cached through
+// provider.Cached
 type KitsuComicInfoProvider struct {
-	cache map[string]MangaInfo
+	client *req.Client
 }
 
-func NewKitsuProvider() *KitsuComicInfoProvider {
-	return &KitsuComicInfoProvider{cache: map[string]MangaInfo{}}
+func init() {
+	provider.Register(provider.Registration{
+		Name: "kitsu",
+		Capabilities: []provider.Capability{
+			provider.CapabilityChapter,
+			provider.CapabilitySearch,
+			provider.CapabilityByID,
+			provider.CapabilitySeries,
+		},
+		Priority: 30,
+		New: func(map[string]string) (provider.ComicInfoProvider, error) {
+			return NewKitsuProvider(nil), nil
+		},
+	})
 }
 
+// NewKitsuProvider calls the api with client, nil uses DefaultClient
+func NewKitsuProvider(client *req.Client) *KitsuComicInfoProvider {
+	if client == nil {
+		client = DefaultClient
+	}
+	return &KitsuComicInfoProvider{client}
+}
+
+func (p *KitsuComicInfoProvider) mangaInfo(
+	ctx context.Context,
+	series string,
+) (MangaInfo, error) {
+	candidates, err := p.SearchSeries(ctx, series)
+	if err != nil {
+		return MangaInfo{}, err
+	}
+	best, err := provider.Best(series, candidates)
+	if err != nil {
+		return MangaInfo{}, err
+	}
+
+	return p.mangaInfoByID(ctx, best.ProviderID)
+}
+
+func (p *KitsuComicInfoProvider) mangaInfoByID(
+	ctx context.Context,
+	id string,
+) (MangaInfo, error) {
+	data, err := GetMangaByID(ctx, p.client, id)
+	if err != nil {
+		return MangaInfo{}, err
+	}
+	return ParseMangaInfo(data)
+}
+
+func (p *KitsuComicInfoProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	data, err := GetSearchByName(ctx, p.client, series)
+	if err != nil {
+		return nil, err
+	}
+	list, err := ParseMangaList(data)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Rank(series, ParseSearchCandidates(list)), nil
+}
+
+func (p *KitsuComicInfoProvider) Name() string { return "kitsu" }
+
 func (p *KitsuComicInfoProvider) ProvideChapter(
 	ctx context.Context, series, chapter string,
 ) (*standard.ComicInfoChapter, error) {
-	mangaInfo, ok := p.cache[series]
-	if !ok {
-		seriesRes := GetSearchByName(series)
-		mangaURL := ParseMangaListSelfLink(seriesRes)
-		mangaInfoRes := GetURL(mangaURL)
-		mangaInfo = ParseMangaInfo(mangaInfoRes)
-		p.cache[series] = mangaInfo
+	mangaInfo, err := p.mangaInfo(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.provideChapter(ctx, mangaInfo, chapter)
+}
+
+func (p *KitsuComicInfoProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	mangaInfo, err := p.mangaInfoByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	info := GetMangaChapterInfo(mangaInfo.Data.ID, chapter)
-	chapterInfo := ParseMangaChapter(info)
+	return p.provideChapter(ctx, mangaInfo, chapter)
+}
+
+func (p *KitsuComicInfoProvider) provideChapter(
+	ctx context.Context,
+	mangaInfo MangaInfo,
+	chapter string,
+) (*standard.ComicInfoChapter, error) {
+	info, err := GetMangaChapterInfo(ctx, p.client, mangaInfo.Data.ID, chapter)
+	if err != nil {
+		return nil, err
+	}
+	chapterInfo, err := ParseMangaChapter(info)
+	if err != nil {
+		return nil, err
+	}
 	return ParseToComicInfoChapter(mangaInfo, chapterInfo)
 }
+
+func (p *KitsuComicInfoProvider) ProvideSeries(
+	ctx context.Context, series string,
+) (*standard.ComicInfoSeries, error) {
+	mangaInfo, err := p.mangaInfo(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseToComicInfoSeries(mangaInfo)
+}
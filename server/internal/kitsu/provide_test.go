@@ -0,0 +1,100 @@
+package kitsu
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/pkg/req/reqtest"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+func TestProvideChapter(t *testing.T) {
+	p := NewKitsuProvider(reqtest.Client(t, "testdata", DefaultBaseURL))
+
+	got, err := p.ProvideChapter(context.Background(), "Berserk", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &standard.ComicInfoChapter{
+		Title:           "Chapter 1 - The Black Swordsman",
+		Series:          "Berserk",
+		Number:          "1",
+		Volume:          1,
+		AlternateSeries: "ベルセルク",
+		Summary:         "Guts, a former mercenary now known as the \"Black Swordsman,\" is out for revenge.",
+		Notes:           "Autogenerated with yuzu 🍋",
+		Writer:          standard.List{"Kentarou Miura"},
+		Penciller:       standard.List{"Kentarou Miura"},
+		CoverArtist:     standard.List{"Kentarou Miura"},
+		Genre:           standard.List{"Action", "Adventure", "Drama", "Fantasy", "Horror"},
+		Tags:            standard.List{"Action", "Fantasy", "Dark Fantasy", "Demon", "Violence"},
+		PageCount:       48,
+		LanguageISO:     "en",
+		Manga:           "YesAndRightToLeft",
+		Characters:      standard.List{"Guts", "Griffith", "Casca"},
+		AgeRating:       "R",
+		CommunityRating: 4.3175,
+		ProviderID:      "38",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProvideChapter() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestProvideSeries(t *testing.T) {
+	p := NewKitsuProvider(reqtest.Client(t, "testdata", DefaultBaseURL))
+
+	got, err := p.ProvideSeries(context.Background(), "Berserk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Status != standard.SeriesStatusOngoing || got.Year != 1989 ||
+		got.Web != "https://kitsu.io/manga/berserk" ||
+		!reflect.DeepEqual(got.Tags, standard.List{"Action", "Fantasy", "Dark Fantasy", "Demon", "Violence"}) {
+		t.Errorf("ProvideSeries() = %+v", got)
+	}
+}
+
+func TestGenres(t *testing.T) {
+	var info MangaInfo
+	for _, r := range []struct{ typ, name string }{
+		{"categories", "Dark Fantasy"},
+		{"categories", "Violence"},
+	} {
+		res := Resource{Type: r.typ}
+		res.Attributes.Title = r.name
+		info.Included = append(info.Included, res)
+	}
+
+	genre, tags := genres(info)
+	want := standard.List{"Dark Fantasy", "Violence"}
+	if !reflect.DeepEqual(genre, want) || !reflect.DeepEqual(tags, want) {
+		t.Errorf("genres() = %v, %v, want categories for both", genre, tags)
+	}
+}
+
+func TestProvideChapterNotFound(t *testing.T) {
+	p := NewKitsuProvider(reqtest.Client(t, "testdata", DefaultBaseURL))
+
+	tests := []struct {
+		name        string
+		id, chapter string
+	}{
+		{"unknown chapter", "38", "999"},
+		{"unknown series", "999999999", "1"},
+		{"invalid id", "../users/1", "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.ProvideChapterByID(context.Background(), tt.id, tt.chapter)
+			if !errors.Is(err, provider.ErrNotFound) {
+				t.Errorf("ProvideChapterByID() error = %v, want not found", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package lib
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// export writes ci next to archive in every format of exporters, formats
+// without a file name go into the archive comment so the archive is only
+// touched when such a format, i.e. ComicBookInfo, was asked for
+func export(
+	ci standard.ComicInfoChapter,
+	exporters []standard.Exporter,
+	archive, chapterNumber string,
+) error {
+	for _, e := range exporters {
+		var buf bytes.Buffer
+		if err := e.Export(&buf, ci); err != nil {
+			return yerr.WithStackf("exporting %s: %w", e.Format(), err)
+		}
+
+		if e.Filename() == "" {
+			if err := setZipComment(archive, buf.String()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		file := path.Join(
+			path.Dir(archive),
+			fmt.Sprintf("%s.%s", chapterNumber, e.Filename()),
+		)
+		if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+			return yerr.WithStackf("writing <%s>: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// setZipComment rewrites archive with the ComicBookInfo comment, entries are
+// copied without recompressing them
+func setZipComment(archive, comment string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return yerr.WithStackf("opening archive <%s>: %w", archive, err)
+	}
+	defer r.Close()
+
+	// lastModified changes on every export, the archive is only rewritten
+	// when the metadata itself does
+	if standard.EqualComicBookInfo(r.Comment, comment) {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(path.Dir(archive), ".yuzu-*.cbz")
+	if err != nil {
+		return yerr.WithStackf("creating temp archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	w := zip.NewWriter(tmp)
+	for _, f := range r.File {
+		if err := w.Copy(f); err != nil {
+			return yerr.WithStackf("copying archive entry <%s>: %w", f.Name, err)
+		}
+	}
+	if err := w.SetComment(comment); err != nil {
+		return yerr.WithStackf("setting archive comment: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return yerr.WithStackf("writing archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return yerr.WithStackf("closing temp archive: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), archive); err != nil {
+		return yerr.WithStackf("replacing archive <%s>: %w", archive, err)
+	}
+
+	return nil
+}
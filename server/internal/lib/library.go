@@ -2,21 +2,70 @@ package lib
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
 	"path"
-	"regexp"
+	"slices"
 
-	"github.com/vyxn/yuzu/internal/kitsu"
+	"github.com/jmoiron/sqlx"
+	"github.com/vyxn/yuzu/internal/cache"
+	"github.com/vyxn/yuzu/internal/config"
+	"github.com/vyxn/yuzu/internal/override"
 	"github.com/vyxn/yuzu/internal/provider"
-	// "github.com/vyxn/yuzu/internal/provider/myanimelist"
+	"github.com/vyxn/yuzu/internal/provider/local"
+	"github.com/vyxn/yuzu/internal/standard"
 )
 
-var re = regexp.MustCompile(`(?i)^.*?(?:chapter|ch|c)?\s?(\d+).*\.cbz$`)
+// Process tags every chapter of every series in dir with the providers and
+// merge policy stored in db, manual overrides stored there are applied last
+// and provider responses are cached there too, exporters select the metadata
+// formats written for each chapter and default to ComicInfo
+func Process(
+	db *sqlx.DB,
+	dir string,
+	exporters ...standard.Exporter,
+) error {
+	ctx := context.Background()
+	if len(exporters) == 0 {
+		exporters = []standard.Exporter{standard.ComicInfoExporter{}}
+	}
+
+	cfg := provider.ProvidersConfig{}
+	if err := config.Get(ctx, db, provider.ProvidersConfigKey, &cfg); err != nil {
+		return err
+	}
+	policy := provider.DefaultMergePolicy()
+	err := config.Get(ctx, db, provider.MergePolicyConfigKey, &policy)
+	if err != nil {
+		return err
+	}
+	rc, err := cache.Load(ctx, db)
+	if err != nil {
+		return err
+	}
 
-func Process(dir string) error {
-	p := kitsu.NewKitsuProvider()
-	// p := myanimelist.NewMyAnimeListProvider()
+	// the metadata local reads back is what earlier scans wrote, merging it
+	// would bring back dropped values and average ratings with themselves,
+	// processChapter only fills gaps from it
+	ps := slices.DeleteFunc(
+		cfg.Providers(),
+		func(p provider.ComicInfoProvider) bool {
+			_, ok := p.(*local.LocalComicInfoProvider)
+			return ok
+		},
+	)
+	for i, p := range ps {
+		ps[i] = provider.Cached(p, rc)
+	}
+	s := scan{
+		db:        db,
+		providers: ps,
+		policy:    policy,
+		exporters: exporters,
+	}
 
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -24,63 +73,171 @@ func Process(dir string) error {
 	}
 
 	for _, e := range entries {
-		if e.Type().IsDir() {
-			processSeries(p, path.Join(dir, e.Name()), e.Name())
+		if !e.Type().IsDir() {
+			continue
+		}
+		err := s.processSeries(ctx, path.Join(dir, e.Name()), e.Name())
+		if err != nil {
+			slog.Warn(
+				"skipping series",
+				slog.String("series", e.Name()),
+				slog.Any("err", err),
+			)
 		}
 	}
 
 	return nil
 }
 
-func processSeries(p provider.ComicInfoProvider, dir, series string) error {
+// scan holds what every chapter of a library scan is tagged with
+type scan struct {
+	db        *sqlx.DB
+	providers []provider.ComicInfoProvider
+	policy    provider.MergePolicy
+	exporters []standard.Exporter
+}
+
+func (s scan) processSeries(ctx context.Context, dir, series string) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return err
 	}
 
+	// chapters are tagged even when no provider knows the series as a whole
+	if err := writeSeriesJSON(ctx, s.providers, dir, series); err != nil {
+		slog.Warn(
+			"skipping series.json",
+			slog.String("series", series),
+			slog.Any("err", err),
+		)
+	}
+
 	for _, e := range entries {
-		if !e.Type().IsDir() {
-			processChapter(p, dir, series, e.Name())
+		if e.Type().IsDir() {
+			continue
+		}
+		if err := s.processChapter(ctx, dir, series, e.Name()); err != nil {
+			slog.Warn(
+				"skipping chapter",
+				slog.String("series", series),
+				slog.String("file", e.Name()),
+				slog.Any("err", err),
+			)
 		}
 	}
 
 	return nil
 }
 
-func processChapter(
-	p provider.ComicInfoProvider,
+// processChapter merges what every provider knows about chapter, providers
+// that fail are left out and the metadata written by earlier scans fills
+// the gaps, or everything when the apis are down
+func (s scan) processChapter(
+	ctx context.Context,
 	dir, series, chapter string,
 ) error {
-	if path.Ext(chapter) != ".cbz" {
+	chapterNumber, ok := local.ChapterNumber(chapter)
+	if !ok {
 		return nil
 	}
+	slog.Debug(
+		"matched chapter",
+		slog.String("file", chapter),
+		slog.String("chapter", chapterNumber),
+	)
+
+	sidecar := path.Join(dir, fmt.Sprintf("%s.ComicInfo.xml", chapterNumber))
+	existing, err := readExisting(sidecar, path.Join(dir, chapter))
+	if err != nil {
+		slog.Warn(
+			"ignoring unreadable comicinfo",
+			slog.String("file", sidecar),
+			slog.Any("err", err),
+		)
+		existing = nil
+	}
 
-	matches := re.FindStringSubmatch(chapter)
-	if len(matches) > 1 {
-		fmt.Printf(
-			"MATCH: %-25s -> Chapter %s -> %+v\n",
-			chapter,
-			matches[1],
-			matches,
+	ci, _, failed, err := provider.MergedComicInfoChapter(
+		ctx,
+		provider.MergeOptions{Policy: s.policy},
+		series,
+		chapterNumber,
+		s.providers...,
+	)
+	for _, name := range slices.Sorted(maps.Keys(failed)) {
+		slog.Debug(
+			"provider failed",
+			slog.String("provider", name),
+			slog.String("chapter", chapter),
+			slog.Any("err", failed[name]),
 		)
-		chapterNumber := matches[1]
+	}
+	switch {
+	case err == nil:
+	case existing != nil:
+		ci = &standard.ComicInfoChapter{}
+	default:
+		return err
+	}
 
-		ci, err := p.ProvideChapter(context.Background(), series, chapterNumber)
-		if err != nil {
-			return err
-		}
+	if existing != nil {
+		// keep unknown elements and fields providers left empty, lists
+		// aren't joined so values dropped upstream go away
+		provider.FillZero(ci, existing)
+	}
 
-		f, err := os.Create(
-			path.Join(dir, fmt.Sprintf("%s.ComicInfo.xml", chapterNumber)),
-		)
-		if err != nil {
-			return err
-		}
+	// applied after the existing metadata so locked fields stay as set
+	ov, err := override.Lookup(ctx, s.db, series, chapterNumber)
+	if err != nil {
+		return err
+	}
+	if ov != nil {
+		ov.Apply(ci, provider.Provenance{})
+	}
 
-		if err := ci.Encode(f); err != nil {
-			return err
-		}
+	pages, err := readPages(path.Join(dir, chapter))
+	if err != nil {
+		return err
 	}
+	ci.Pages = pages
+	ci.PageCount = len(pages)
 
-	return nil
+	ci.Repair()
+	return export(*ci, s.exporters, path.Join(dir, chapter), chapterNumber)
+}
+
+// readExisting decodes the metadata already written for a chapter, the sidecar
+// takes precedence over the ComicInfo.xml inside the archive
+func readExisting(sidecar, archive string) (*standard.ComicInfoChapter, error) {
+	ci, err := standard.ReadComicInfoFile(sidecar)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return ci, err
+	}
+
+	ci, err = standard.ReadComicInfoCBZ(archive)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return ci, err
+}
+
+// writeSeriesJSON writes series.json into dir with the series metadata of
+// every provider that knows about series
+func writeSeriesJSON(
+	ctx context.Context,
+	ps []provider.ComicInfoProvider,
+	dir, series string,
+) error {
+	cs, err := provider.MergedComicInfoSeries(ctx, series, ps...)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path.Join(dir, standard.SeriesJSONFilename))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return cs.EncodeSeriesJSON(f)
 }
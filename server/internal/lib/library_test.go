@@ -0,0 +1,147 @@
+package lib
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/override"
+	"github.com/vyxn/yuzu/internal/pkg/dbtest"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// stubProvider always knows the same chapter
+type stubProvider struct{}
+
+func (stubProvider) Name() string { return "stub" }
+
+func (stubProvider) ProvideChapter(
+	ctx context.Context, series, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	return &standard.ComicInfoChapter{
+		Series:          series,
+		Number:          chapter,
+		Genre:           standard.List{"Action"},
+		CommunityRating: 4,
+	}, nil
+}
+
+func (stubProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	return stubProvider{}.ProvideChapter(ctx, id, chapter)
+}
+
+func (stubProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	return nil, provider.ErrNotFound
+}
+
+func init() {
+	provider.Register(provider.Registration{
+		Name:         "stub",
+		Capabilities: []provider.Capability{provider.CapabilityChapter},
+		Priority:     10,
+		New: func(map[string]string) (provider.ComicInfoProvider, error) {
+			return stubProvider{}, nil
+		},
+	})
+}
+
+// writeArchive creates a cbz holding a single png page
+func writeArchive(t *testing.T, file string) {
+	t.Helper()
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	page, err := w.Create("001.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := image.NewGray(image.Rect(0, 0, 2, 3))
+	if err := png.Encode(page, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessDeletedOverride(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	t.Setenv("LIBRARY_DIR", root)
+	dir := filepath.Join(root, "Berserk")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeArchive(t, filepath.Join(dir, "Berserk 001.cbz"))
+	sidecar := filepath.Join(dir, "001.ComicInfo.xml")
+
+	db := dbtest.Open(t)
+	err := override.Put(ctx, db, override.Override{
+		Series:  "Berserk",
+		Chapter: "1",
+		Fields: map[string]json.RawMessage{
+			"Genre":           json.RawMessage(`["Drama"]`),
+			"CommunityRating": json.RawMessage(`2`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Process(db, root); err != nil {
+		t.Fatal(err)
+	}
+	ci, err := standard.ReadComicInfoFile(sidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ci.Genre, standard.List{"Drama"}) ||
+		ci.CommunityRating != 2 {
+		t.Fatalf(
+			"with the override Genre = %q, CommunityRating = %v",
+			ci.Genre,
+			ci.CommunityRating,
+		)
+	}
+
+	if _, err := override.Delete(ctx, db, "Berserk", "1"); err != nil {
+		t.Fatal(err)
+	}
+	// twice so values read back from the sidecar would show up
+	for range 2 {
+		if err := Process(db, root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ci, err = standard.ReadComicInfoFile(sidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ci.Genre, standard.List{"Action"}) ||
+		ci.CommunityRating != 4 {
+		t.Errorf(
+			"without the override Genre = %q, CommunityRating = %v, want %q, 4",
+			ci.Genre,
+			ci.CommunityRating,
+			standard.List{"Action"},
+		)
+	}
+	if ci.PageCount != 1 {
+		t.Errorf("PageCount = %d, want 1", ci.PageCount)
+	}
+}
@@ -0,0 +1,129 @@
+package lib
+
+import (
+	"archive/zip"
+	"cmp"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+var imageExts = []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".avif"}
+
+func isImage(name string) bool {
+	return slices.Contains(imageExts, strings.ToLower(path.Ext(name)))
+}
+
+// readPages lists the images inside a cbz in reading order and returns the
+// matching ComicInfo page entries. The first image is flagged as the cover
+// and any image wider than it is tall is flagged as a double page spread.
+func readPages(file string) ([]standard.ComicPageInfo, error) {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, yerr.WithStackf("opening archive <%s>: %w", file, err)
+	}
+	defer r.Close()
+
+	files := []*zip.File{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !isImage(f.Name) {
+			continue
+		}
+		// skip macOS metadata and hidden files
+		if strings.HasPrefix(path.Base(f.Name), ".") ||
+			strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		files = append(files, f)
+	}
+	slices.SortFunc(files, func(a, b *zip.File) int {
+		return naturalCompare(a.Name, b.Name)
+	})
+
+	pages := make([]standard.ComicPageInfo, 0, len(files))
+	for i, f := range files {
+		page := standard.ComicPageInfo{
+			Image:     i,
+			Type:      standard.PageTypeStory,
+			ImageSize: int64(f.UncompressedSize64),
+		}
+		if i == 0 {
+			page.Type = standard.PageTypeFrontCover
+		}
+
+		w, h, err := imageDimensions(f)
+		if err != nil {
+			return nil, err
+		}
+		if w > 0 && h > 0 {
+			page.ImageWidth = w
+			page.ImageHeight = h
+			page.DoublePage = w > h
+		}
+
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// naturalCompare orders names case insensitively with runs of digits compared
+// by value, so page2 comes before page10
+func naturalCompare(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for a != "" && b != "" {
+		da, db := digits(a), digits(b)
+		if da == 0 || db == 0 {
+			if c := cmp.Compare(a[0], b[0]); c != 0 {
+				return c
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		na := strings.TrimLeft(a[:da], "0")
+		nb := strings.TrimLeft(b[:db], "0")
+		if c := cmp.Or(
+			cmp.Compare(len(na), len(nb)),
+			strings.Compare(na, nb),
+			// 01 before 1 keeps the order total
+			cmp.Compare(db, da),
+		); c != 0 {
+			return c
+		}
+		a, b = a[da:], b[db:]
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// digits returns the length of the run of ascii digits s starts with
+func digits(s string) int {
+	n := 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// imageDimensions returns 0, 0 for formats the standard library can't decode
+func imageDimensions(f *zip.File) (int, int, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return 0, 0, yerr.WithStackf("opening archive entry <%s>: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	cfg, _, err := image.DecodeConfig(rc)
+	if err != nil {
+		return 0, 0, nil
+	}
+
+	return cfg.Width, cfg.Height, nil
+}
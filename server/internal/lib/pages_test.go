@@ -0,0 +1,44 @@
+package lib
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{
+			"unpadded numbers",
+			[]string{"page10.jpg", "page2.jpg", "page1.jpg"},
+			[]string{"page1.jpg", "page2.jpg", "page10.jpg"},
+		},
+		{
+			"padded numbers",
+			[]string{"010.png", "002.png", "001.png"},
+			[]string{"001.png", "002.png", "010.png"},
+		},
+		{
+			"case and folders",
+			[]string{"ch1/Page3.jpg", "ch1/page12.jpg", "Cover.jpg"},
+			[]string{"ch1/Page3.jpg", "ch1/page12.jpg", "Cover.jpg"},
+		},
+		{
+			"leading zeros tie",
+			[]string{"1.jpg", "01.jpg"},
+			[]string{"01.jpg", "1.jpg"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Clone(tt.in)
+			slices.SortFunc(got, naturalCompare)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sorted %v = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
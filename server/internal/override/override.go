@@ -0,0 +1,291 @@
+// Package override stores manual corrections of chapter metadata in the
+// override table
+package override
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// Override corrects the metadata of a chapter, an empty Chapter applies to
+// every chapter of the series
+type Override struct {
+	Series  string `json:"series"`
+	Chapter string `json:"chapter"`
+	// Fields maps ComicInfo field names to their corrected value
+	Fields map[string]json.RawMessage `json:"fields"`
+	// Locked lists ComicInfo field names providers may never change
+	Locked    []string  `json:"locked"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type row struct {
+	Series    string    `db:"series"`
+	Chapter   string    `db:"chapter"`
+	Fields    string    `db:"fields"`
+	Locked    string    `db:"locked"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (r row) override() (Override, error) {
+	o := Override{
+		Series:    r.Series,
+		Chapter:   r.Chapter,
+		UpdatedAt: r.UpdatedAt,
+	}
+	if err := json.Unmarshal([]byte(r.Fields), &o.Fields); err != nil {
+		return o, yerr.WithStackf("decoding override fields: %w", err)
+	}
+	if err := json.Unmarshal([]byte(r.Locked), &o.Locked); err != nil {
+		return o, yerr.WithStackf("decoding override locks: %w", err)
+	}
+	return o, nil
+}
+
+// Validate checks that every field and lock names a ComicInfo field and that
+// the values fit their field
+func (o Override) Validate() error {
+	if o.Series == "" {
+		return yerr.WithStackf("override needs a series")
+	}
+
+	t := reflect.TypeFor[standard.ComicInfoChapter]()
+	check := func(name string) error {
+		f, ok := t.FieldByName(name)
+		if !ok || !f.IsExported() || name == "XMLName" || name == "ProviderID" {
+			return yerr.WithStackf("unknown comicinfo field <%s>", name)
+		}
+		return nil
+	}
+	for name := range o.Fields {
+		if err := check(name); err != nil {
+			return err
+		}
+	}
+	for _, name := range o.Locked {
+		if err := check(name); err != nil {
+			return err
+		}
+	}
+
+	_, err := o.ComicInfo()
+	return err
+}
+
+// ComicInfo decodes Fields into a ComicInfoChapter
+func (o Override) ComicInfo() (*standard.ComicInfoChapter, error) {
+	ci := &standard.ComicInfoChapter{}
+	if len(o.Fields) == 0 {
+		return ci, nil
+	}
+
+	data, err := json.Marshal(o.Fields)
+	if err != nil {
+		return nil, yerr.WithStackf("encoding override fields: %w", err)
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(ci); err != nil {
+		return nil, yerr.WithStackf("decoding override fields: %w", err)
+	}
+
+	return ci, nil
+}
+
+// Get returns the override stored for series and chapter, nil when there is
+// none
+func Get(
+	ctx context.Context,
+	db *sqlx.DB,
+	series, chapter string,
+) (*Override, error) {
+	var r row
+	err := db.GetContext(
+		ctx,
+		&r,
+		"SELECT * FROM override WHERE series = ? AND chapter = ?",
+		series,
+		chapterKey(chapter),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, yerr.WithStackf(
+			"reading override <%s> <%s>: %w",
+			series,
+			chapter,
+			err,
+		)
+	}
+
+	o, err := r.override()
+	return &o, err
+}
+
+// List returns every override of series, or of every series when series is
+// empty
+func List(
+	ctx context.Context,
+	db *sqlx.DB,
+	series string,
+) ([]Override, error) {
+	rows := []row{}
+	err := db.SelectContext(
+		ctx,
+		&rows,
+		`SELECT * FROM override WHERE ? = '' OR series = ?
+		ORDER BY series, chapter`,
+		series,
+		series,
+	)
+	if err != nil {
+		return nil, yerr.WithStackf("listing overrides <%s>: %w", series, err)
+	}
+
+	out := make([]Override, 0, len(rows))
+	for _, r := range rows {
+		o, err := r.override()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+// Put creates or replaces the override of o.Series and o.Chapter
+func Put(ctx context.Context, db *sqlx.DB, o Override) error {
+	if o.Fields == nil {
+		o.Fields = map[string]json.RawMessage{}
+	}
+	if o.Locked == nil {
+		o.Locked = []string{}
+	}
+
+	fields, err := json.Marshal(o.Fields)
+	if err != nil {
+		return yerr.WithStackf("encoding override fields: %w", err)
+	}
+	locked, err := json.Marshal(o.Locked)
+	if err != nil {
+		return yerr.WithStackf("encoding override locks: %w", err)
+	}
+
+	_, err = db.ExecContext(
+		ctx,
+		`INSERT INTO override (series, chapter, fields, locked, updated_at)
+		VALUES (?, ?, json(?), json(?), CURRENT_TIMESTAMP)
+		ON CONFLICT (series, chapter) DO UPDATE SET
+			fields = excluded.fields,
+			locked = excluded.locked,
+			updated_at = excluded.updated_at`,
+		o.Series,
+		chapterKey(o.Chapter),
+		string(fields),
+		string(locked),
+	)
+	if err != nil {
+		return yerr.WithStackf(
+			"writing override <%s> <%s>: %w",
+			o.Series,
+			o.Chapter,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Delete removes the override of series and chapter, it reports whether
+// there was one
+func Delete(
+	ctx context.Context,
+	db *sqlx.DB,
+	series, chapter string,
+) (bool, error) {
+	res, err := db.ExecContext(
+		ctx,
+		"DELETE FROM override WHERE series = ? AND chapter = ?",
+		series,
+		chapterKey(chapter),
+	)
+	if err != nil {
+		return false, yerr.WithStackf(
+			"deleting override <%s> <%s>: %w",
+			series,
+			chapter,
+			err,
+		)
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Lookup combines the series wide override with the one of chapter, chapter
+// values replace the series ones, lists included, and locks of both apply, it
+// returns nil when neither exists
+func Lookup(
+	ctx context.Context,
+	db *sqlx.DB,
+	series, chapter string,
+) (*provider.Override, error) {
+	found := []*Override{}
+	for _, c := range slices.Compact([]string{"", chapterKey(chapter)}) {
+		o, err := Get(ctx, db, series, c)
+		if err != nil {
+			return nil, err
+		}
+		if o != nil {
+			found = append(found, o)
+		}
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	out := &provider.Override{Fields: &standard.ComicInfoChapter{}}
+	for _, o := range found {
+		ci, err := o.ComicInfo()
+		if err != nil {
+			return nil, err
+		}
+		// later overrides are more specific, their fields go over the others
+		provider.FillZero(ci, out.Fields)
+		out.Fields = ci
+		for _, l := range o.Locked {
+			if !slices.Contains(out.Locked, l) {
+				out.Locked = append(out.Locked, l)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// chapterKey drops leading zeros so "007" from a file name and "7" from the
+// api find the same override
+func chapterKey(chapter string) string {
+	chapter = strings.TrimSpace(chapter)
+	if chapter == "" {
+		return ""
+	}
+	k := strings.TrimLeft(chapter, "0")
+	if k == "" || k[0] == '.' {
+		k = "0" + k
+	}
+	return k
+}
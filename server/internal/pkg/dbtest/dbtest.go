@@ -0,0 +1,38 @@
+// Package dbtest opens throwaway databases with the schema of the app for
+// tests of the packages that read and write it
+package dbtest
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/jmoiron/sqlx"
+)
+
+// Open returns a database in a temporary dir with schema.sql applied, it is
+// closed when the test ends
+func Open(t testing.TB) *sqlx.DB {
+	t.Helper()
+
+	_, file, _, _ := runtime.Caller(0)
+	schema, err := os.ReadFile(
+		filepath.Join(filepath.Dir(file), "..", "..", "..", "schema.sql"),
+	)
+	if err != nil {
+		t.Fatalf("reading schema: %v", err)
+	}
+
+	db, err := sqlx.Open("sqlite", filepath.Join(t.TempDir(), "meta.db"))
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("applying schema: %v", err)
+	}
+	return db
+}
@@ -2,10 +2,16 @@
 package req
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/vyxn/yuzu/internal/pkg/yerr"
@@ -13,44 +19,212 @@ import (
 
 const timeout = 10 * time.Second
 
-var client = &http.Client{Timeout: timeout}
+// ErrNotFound is wrapped by the errors of 404 responses
+var ErrNotFound = errors.New("not found")
 
+// DefaultHTTPClient is used by clients without their own http.Client
+var DefaultHTTPClient = &http.Client{Timeout: timeout}
+
+// Client sends requests to urls relative to BaseURL with HTTP, absolute urls
+// are sent as is, so the zero value works with absolute urls and
+// DefaultHTTPClient
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+	// Validate, when set, checks the body of successful responses so api
+	// errors sent with a 2xx status fail the request and are never cached
+	Validate func(data []byte) error
+}
+
+// NewClient returns a client for the api at baseURL, hc may be nil
+func NewClient(baseURL string, hc *http.Client) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTP: hc}
+}
+
+// URL resolves path against BaseURL
+func (c *Client) URL(path string) string {
+	if c.BaseURL == "" || strings.Contains(path, "://") {
+		return path
+	}
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return c.BaseURL + path
+}
+
+// Get fetches path and returns the response body
+func (c *Client) Get(
+	ctx context.Context,
+	path string,
+	headers map[string]string,
+) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, c.URL(path), headers, nil)
+}
+
+// Post sends body, usually json, to path and returns the response body
+func (c *Client) Post(
+	ctx context.Context,
+	path string,
+	headers map[string]string,
+	body []byte,
+) ([]byte, error) {
+	return c.do(ctx, http.MethodPost, c.URL(path), headers, body)
+}
+
+// Cache keeps response bodies between calls, failures to read or write it
+// are the cache's business and never fail a request
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, data []byte)
+}
+
+type cacheKey struct{}
+
+// noCache never stores anything
+type noCache struct{}
+
+func (noCache) Get(context.Context, string) ([]byte, bool) { return nil, false }
+func (noCache) Set(context.Context, string, []byte)        {}
+
+// WithCache makes requests done with the returned context read from and
+// fill c
+func WithCache(ctx context.Context, c Cache) context.Context {
+	return context.WithValue(ctx, cacheKey{}, c)
+}
+
+// CacheFrom returns the cache set by WithCache, or one that never stores
+func CacheFrom(ctx context.Context) Cache {
+	if c, ok := ctx.Value(cacheKey{}).(Cache); ok {
+		return c
+	}
+	return noCache{}
+}
+
+// SecretParams are query parameters holding credentials, they are left out
+// of cache keys and logs
+var SecretParams = []string{"api_key"}
+
+// Redact drops SecretParams from the query of rawURL
+func Redact(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	found := false
+	for _, p := range SecretParams {
+		found = found || q.Has(p)
+		q.Del(p)
+	}
+	if !found {
+		return rawURL
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Key identifies a request in a cache, the body is hashed so graphql and
+// search queries sent by post get their own entries, credentials in the
+// query are dropped so they aren't stored along with the response
+func Key(method, url string, body []byte) string {
+	url = Redact(url)
+	if len(body) == 0 {
+		return method + " " + url
+	}
+	sum := sha256.Sum256(body)
+	return method + " " + url + " " + hex.EncodeToString(sum[:])
+}
+
+// Get fetches url with DefaultHTTPClient
 func Get(
 	ctx context.Context,
 	url string,
 	headers map[string]string,
 ) ([]byte, error) {
+	return (&Client{}).Get(ctx, url, headers)
+}
+
+// Post sends body, usually json, to url with DefaultHTTPClient
+func Post(
+	ctx context.Context,
+	url string,
+	headers map[string]string,
+	body []byte,
+) ([]byte, error) {
+	return (&Client{}).Post(ctx, url, headers, body)
+}
+
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	url string,
+	headers map[string]string,
+	body []byte,
+) ([]byte, error) {
+	cache := CacheFrom(ctx)
+	key := Key(method, url, body)
+	redacted := Redact(url)
+	if data, ok := cache.Get(ctx, key); ok {
+		slog.Info(
+			"→ cached",
+			slog.String("method", method),
+			slog.String("url", redacted),
+		)
+		return data, nil
+	}
+
 	slog.Info(
 		"→ r",
-		slog.String("method", http.MethodGet),
-		slog.String("url", url),
+		slog.String("method", method),
+		slog.String("url", redacted),
 	)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
 	if err != nil {
-		return nil, yerr.WithStackf("creating request <%s>: %w", url, err)
+		return nil, yerr.WithStackf("creating request <%s>: %w", redacted, err)
 	}
 
 	for k, v := range headers {
 		req.Header.Add(k, v)
 	}
 
-	resp, err := client.Do(req)
+	hc := c.HTTP
+	if hc == nil {
+		hc = DefaultHTTPClient
+	}
+	resp, err := hc.Do(req)
 	if err != nil {
-		return nil, yerr.WithStackf("fetching <%s>: %w", url, err)
+		return nil, yerr.WithStackf("fetching <%s>: %w", redacted, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < http.StatusOK ||
 		resp.StatusCode >= http.StatusMultipleChoices {
 		b, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, yerr.WithStackf(
+				"fetching <%s>: %w",
+				redacted,
+				ErrNotFound,
+			)
+		}
 		return nil, yerr.WithStackf("bad status <%s>: %s", resp.Status, string(b))
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, yerr.WithStackf("reading response body: %w", err)
 	}
+	if c.Validate != nil {
+		if err := c.Validate(data); err != nil {
+			return nil, err
+		}
+	}
 
-	return body, nil
+	cache.Set(ctx, key, data)
+	return data, nil
 }
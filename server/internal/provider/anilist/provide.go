@@ -0,0 +1,367 @@
+// Package anilist implements the provider interface for anilist metadata
+package anilist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/vyxn/yuzu/internal/pkg/req"
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// DefaultBaseURL is the graphql endpoint used by the registered provider
+const DefaultBaseURL = "https://graphql.anilist.co"
+
+const searchQuery = `query ($search: String) {
+  Page(perPage: 10) {
+    media(search: $search, type: MANGA) {
+      id
+      title { romaji english native }
+      synonyms
+      format
+      startDate { year }
+      coverImage { large }
+    }
+  }
+}`
+
+const mediaQuery = `query ($id: Int) {
+  Media(id: $id, type: MANGA) {
+    id
+    title { romaji english native }
+    synonyms
+    description(asHtml: false)
+    status
+    format
+    startDate { year month day }
+    chapters
+    volumes
+    countryOfOrigin
+    averageScore
+    isAdult
+    siteUrl
+    genres
+    tags { name rank isGeneralSpoiler isMediaSpoiler }
+    coverImage { large }
+    staff(perPage: 25) { edges { role node { name { full } } } }
+    characters(perPage: 50, sort: [ROLE, RELEVANCE]) { nodes { name { full } } }
+  }
+}`
+
+func init() {
+	provider.Register(provider.Registration{
+		Name: "anilist",
+		Capabilities: []provider.Capability{
+			provider.CapabilityChapter,
+			provider.CapabilitySearch,
+			provider.CapabilityByID,
+			provider.CapabilitySeries,
+		},
+		Priority: 25,
+		New: func(map[string]string) (provider.ComicInfoProvider, error) {
+			return NewAniListProvider(nil), nil
+		},
+	})
+}
+
+type AniListComicInfoProvider struct {
+	client *req.Client
+}
+
+// NewAniListProvider sends its queries with client, nil uses DefaultBaseURL
+func NewAniListProvider(client *req.Client) *AniListComicInfoProvider {
+	if client == nil {
+		client = req.NewClient(DefaultBaseURL, nil)
+	}
+	return &AniListComicInfoProvider{client}
+}
+
+func (p *AniListComicInfoProvider) Name() string { return "anilist" }
+
+func (p *AniListComicInfoProvider) ProvideChapter(
+	ctx context.Context, series, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	media, err := p.findMedia(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get anilist comicinfo: %w", err)
+	}
+
+	return parseChapter(media), nil
+}
+
+func (p *AniListComicInfoProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	media, err := p.media(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get anilist comicinfo: %w", err)
+	}
+
+	return parseChapter(media), nil
+}
+
+func (p *AniListComicInfoProvider) ProvideSeries(
+	ctx context.Context, series string,
+) (*standard.ComicInfoSeries, error) {
+	media, err := p.findMedia(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get anilist series info: %w", err)
+	}
+
+	return parseSeries(media), nil
+}
+
+func (p *AniListComicInfoProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	var res PageResponse
+	err := p.query(ctx, searchQuery, map[string]any{"search": series}, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]provider.SeriesCandidate, 0, len(res.Page.Media))
+	for _, m := range res.Page.Media {
+		candidates = append(candidates, provider.SeriesCandidate{
+			ProviderID: strconv.Itoa(m.ID),
+			Titles:     titles(&m),
+			Year:       m.StartDate.Year,
+			Type:       m.Format,
+			CoverURL:   m.CoverImage.Large,
+		})
+	}
+
+	return provider.Rank(series, candidates), nil
+}
+
+// findMedia fetches the details of the best search match for series
+func (p *AniListComicInfoProvider) findMedia(
+	ctx context.Context,
+	series string,
+) (*Media, error) {
+	candidates, err := p.SearchSeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("finding series: %w", err)
+	}
+	best, err := provider.Best(series, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("finding series: %w", err)
+	}
+
+	return p.media(ctx, best.ProviderID)
+}
+
+func (p *AniListComicInfoProvider) media(
+	ctx context.Context,
+	id string,
+) (*Media, error) {
+	mediaID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, yerr.WithStackf("parsing anilist id <%s>: %w", id, err)
+	}
+
+	var res MediaResponse
+	err = p.query(ctx, mediaQuery, map[string]any{"id": mediaID}, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Media, nil
+}
+
+// query runs a graphql query and decodes its data into res
+func (p *AniListComicInfoProvider) query(
+	ctx context.Context,
+	query string,
+	variables map[string]any,
+	res any,
+) error {
+	body, err := json.Marshal(map[string]any{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return yerr.WithStackf("encoding graphql query: %w", err)
+	}
+
+	data, err := p.client.Post(
+		ctx,
+		"",
+		map[string]string{
+			"Content-Type": "application/json",
+			"Accept":       "application/json",
+		},
+		body,
+	)
+	if err != nil {
+		return err
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+	if len(envelope.Errors) > 0 {
+		return yerr.WithStackf(
+			"anilist response: %s",
+			envelope.Errors[0].Message,
+		)
+	}
+
+	if err := json.Unmarshal(envelope.Data, res); err != nil {
+		return yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+
+	return nil
+}
+
+// roles maps AniList staff roles, without their "(Volumes 1-3)" style
+// qualifier, to the ComicInfo credit fields they fill
+var roles = map[string][]string{
+	"story & art":      {"Writer", "Penciller"},
+	"story":            {"Writer"},
+	"original story":   {"Writer"},
+	"original creator": {"Writer"},
+	"art":              {"Penciller"},
+	"illustration":     {"Penciller"},
+	"lettering":        {"Letterer"},
+	"editing":          {"Editor"},
+	"translator":       {"Translator"},
+}
+
+// countries maps AniList's country of origin to the reading direction and
+// the original language of the series
+var countries = map[string]struct{ manga, language string }{
+	"JP": {"YesAndRightToLeft", "ja"},
+	"KR": {"Yes", "ko"},
+	"CN": {"Yes", "zh"},
+	"TW": {"Yes", "zh"},
+}
+
+// parseChapter maps the series metadata AniList has, it knows nothing about
+// single chapters so their date is left to other providers
+func parseChapter(m *Media) *standard.ComicInfoChapter {
+	ci := &standard.ComicInfoChapter{
+		Summary:         provider.StripHTML(m.Description),
+		Notes:           "Autogenerated with yuzu 🍋",
+		Count:           m.Chapters,
+		Genre:           standard.NewList(m.Genres...),
+		Tags:            tags(m),
+		CommunityRating: rating(m.AverageScore),
+		Web:             m.SiteURL,
+		ProviderID:      strconv.Itoa(m.ID),
+	}
+	if m.IsAdult {
+		ci.AgeRating = "Adults Only 18+"
+	}
+
+	credits := map[string]*standard.List{
+		"Writer":     &ci.Writer,
+		"Penciller":  &ci.Penciller,
+		"Letterer":   &ci.Letterer,
+		"Editor":     &ci.Editor,
+		"Translator": &ci.Translator,
+	}
+	for _, e := range m.Staff.Edges {
+		role, _, _ := strings.Cut(e.Role, "(")
+		role = strings.ToLower(strings.TrimSpace(role))
+		for _, field := range roles[role] {
+			*credits[field] = credits[field].Add(e.Node.Name.Full)
+		}
+	}
+	for _, c := range m.Characters.Nodes {
+		ci.Characters = ci.Characters.Add(c.Name.Full)
+	}
+
+	country := countries[m.CountryOfOrigin]
+	ci.Manga = country.manga
+	standard.GetTitlePreference().Apply(ci, titles(m))
+	if ci.LanguageISO == "" {
+		ci.LanguageISO = country.language
+	}
+
+	return ci
+}
+
+// statuses maps AniList's media status to the standard series status
+var statuses = map[string]string{
+	"RELEASING":        standard.SeriesStatusOngoing,
+	"FINISHED":         standard.SeriesStatusEnded,
+	"HIATUS":           standard.SeriesStatusHiatus,
+	"CANCELLED":        standard.SeriesStatusAbandoned,
+	"NOT_YET_RELEASED": standard.SeriesStatusUnknown,
+}
+
+func parseSeries(m *Media) *standard.ComicInfoSeries {
+	cs := &standard.ComicInfoSeries{
+		Summary:         provider.StripHTML(m.Description),
+		Status:          statuses[m.Status],
+		Year:            m.StartDate.Year,
+		Count:           m.Chapters,
+		VolumeCount:     m.Volumes,
+		Genre:           standard.NewList(m.Genres...),
+		Tags:            tags(m),
+		Manga:           countries[m.CountryOfOrigin].manga,
+		CommunityRating: rating(m.AverageScore),
+		CoverURL:        m.CoverImage.Large,
+		Web:             m.SiteURL,
+		ProviderID:      strconv.Itoa(m.ID),
+	}
+	if m.IsAdult {
+		cs.AgeRating = "Adults Only 18+"
+	}
+
+	standard.GetTitlePreference().ApplySeries(cs, titles(m))
+	if cs.LanguageISO == "" {
+		cs.LanguageISO = countries[m.CountryOfOrigin].language
+	}
+
+	return cs
+}
+
+// titles lists every title of the media, AniList's native title is in the
+// language of the country of origin
+func titles(m *Media) []standard.Title {
+	native := countries[m.CountryOfOrigin].language
+	romaji := ""
+	if native != "" {
+		romaji = native + "-Latn"
+	}
+
+	titles := []standard.Title{
+		{Lang: romaji, Value: m.Title.Romaji},
+		{Lang: "en", Value: m.Title.English},
+		{Lang: native, Value: m.Title.Native},
+	}
+	for _, s := range m.Synonyms {
+		titles = append(titles, standard.Title{Value: s})
+	}
+	return titles
+}
+
+// tags keeps the tags that don't spoil the story
+func tags(m *Media) standard.List {
+	out := standard.List{}
+	for _, t := range m.Tags {
+		if !t.IsGeneralSpoiler && !t.IsMediaSpoiler {
+			out = out.Add(t.Name)
+		}
+	}
+	return out
+}
+
+// rating converts AniList's 0-100 score to the ComicInfo 0-5 range
+func rating(score int) float64 {
+	return math.Round(float64(score)/2) / 10
+}
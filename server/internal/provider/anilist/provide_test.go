@@ -0,0 +1,57 @@
+package anilist
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/pkg/req/reqtest"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+func TestProvideChapter(t *testing.T) {
+	p := NewAniListProvider(reqtest.Client(t, "testdata", DefaultBaseURL))
+
+	got, err := p.ProvideChapter(context.Background(), "Berserk", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &standard.ComicInfoChapter{
+		Series:          "Berserk",
+		AlternateSeries: "ベルセルク",
+		Summary:         "Guts, a former mercenary now known as the \"Black Swordsman\", is out for revenge.\n\n(Source: Dark Horse)",
+		Notes:           "Autogenerated with yuzu 🍋",
+		Count:           380,
+		Writer:          standard.List{"Kentarou Miura", "Kouji Mori"},
+		Penciller:       standard.List{"Kentarou Miura", "Studio Gaga"},
+		Translator:      standard.List{"Jason DeAngelis"},
+		Genre:           standard.List{"Action", "Adventure", "Drama"},
+		Tags:            standard.List{"Dark Fantasy", "Gore"},
+		Web:             "https://anilist.co/manga/30002",
+		LanguageISO:     "en",
+		Manga:           "YesAndRightToLeft",
+		Characters:      standard.List{"Guts", "Griffith", "Casca"},
+		AgeRating:       "Adults Only 18+",
+		CommunityRating: 4.7,
+		ProviderID:      "30002",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProvideChapter() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestProvideSeries(t *testing.T) {
+	p := NewAniListProvider(reqtest.Client(t, "testdata", DefaultBaseURL))
+
+	got, err := p.ProvideSeries(context.Background(), "Berserk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Status != standard.SeriesStatusHiatus || got.VolumeCount != 41 ||
+		got.Year != 1989 ||
+		got.CoverURL != "https://s4.anilist.co/file/anilistcdn/media/manga/cover/large/bx30002.jpg" {
+		t.Errorf("ProvideSeries() = %+v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package anilist
+
+// Media is the AniList manga as requested by mediaQuery
+type Media struct {
+	ID    int `json:"id"`
+	Title struct {
+		Romaji  string `json:"romaji"`
+		English string `json:"english"`
+		Native  string `json:"native"`
+	} `json:"title"`
+	Synonyms        []string  `json:"synonyms"`
+	Description     string    `json:"description"`
+	Status          string    `json:"status"`
+	Format          string    `json:"format"`
+	StartDate       FuzzyDate `json:"startDate"`
+	Chapters        int       `json:"chapters"`
+	Volumes         int       `json:"volumes"`
+	CountryOfOrigin string    `json:"countryOfOrigin"`
+	AverageScore    int       `json:"averageScore"`
+	IsAdult         bool      `json:"isAdult"`
+	SiteURL         string    `json:"siteUrl"`
+	Genres          []string  `json:"genres"`
+	Tags            []struct {
+		Name             string `json:"name"`
+		Rank             int    `json:"rank"`
+		IsGeneralSpoiler bool   `json:"isGeneralSpoiler"`
+		IsMediaSpoiler   bool   `json:"isMediaSpoiler"`
+	} `json:"tags"`
+	CoverImage struct {
+		Large string `json:"large"`
+	} `json:"coverImage"`
+	Staff struct {
+		Edges []struct {
+			Role string `json:"role"`
+			Node struct {
+				Name struct {
+					Full string `json:"full"`
+				} `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"staff"`
+	Characters struct {
+		Nodes []struct {
+			Name struct {
+				Full string `json:"full"`
+			} `json:"name"`
+		} `json:"nodes"`
+	} `json:"characters"`
+}
+
+// FuzzyDate is an AniList date where any part may be missing
+type FuzzyDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+// MediaResponse is the response to mediaQuery
+type MediaResponse struct {
+	Media Media `json:"Media"`
+}
+
+// PageResponse is the response to searchQuery
+type PageResponse struct {
+	Page struct {
+		Media []Media `json:"media"`
+	} `json:"Page"`
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/vyxn/yuzu/internal/pkg/req"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// ResponseCache hands out the response cache of each provider
+type ResponseCache interface {
+	For(provider string) req.Cache
+}
+
+// Cached makes every request of p go through its cache in c, the result
+// still implements ComicInfoSeriesProvider when p does
+func Cached(p ComicInfoProvider, c ResponseCache) ComicInfoProvider {
+	cp := cached{p: p, cache: c.For(p.Name())}
+	if _, ok := p.(ComicInfoSeriesProvider); ok {
+		return cachedSeries{cp}
+	}
+	return cp
+}
+
+type cached struct {
+	p     ComicInfoProvider
+	cache req.Cache
+}
+
+func (c cached) Name() string { return c.p.Name() }
+
+func (c cached) ProvideChapter(
+	ctx context.Context, series, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	return c.p.ProvideChapter(req.WithCache(ctx, c.cache), series, chapter)
+}
+
+func (c cached) SearchSeries(
+	ctx context.Context, series string,
+) ([]SeriesCandidate, error) {
+	return c.p.SearchSeries(req.WithCache(ctx, c.cache), series)
+}
+
+func (c cached) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	return c.p.ProvideChapterByID(req.WithCache(ctx, c.cache), id, chapter)
+}
+
+type cachedSeries struct {
+	cached
+}
+
+func (c cachedSeries) ProvideSeries(
+	ctx context.Context, series string,
+) (*standard.ComicInfoSeries, error) {
+	return c.p.(ComicInfoSeriesProvider).ProvideSeries(
+		req.WithCache(ctx, c.cache),
+		series,
+	)
+}
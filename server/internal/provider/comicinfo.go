@@ -8,8 +8,28 @@ import (
 )
 
 type ComicInfoProvider interface {
+	// Name identifies the provider in merge policies and api parameters
+	Name() string
 	ProvideChapter(
 		ctx context.Context,
 		series, chapter string,
 	) (*standard.ComicInfoChapter, error)
+	// SearchSeries returns the candidates for series ranked by Score, best
+	// match first
+	SearchSeries(ctx context.Context, series string) ([]SeriesCandidate, error)
+	// ProvideChapterByID skips the search and uses the series with the given
+	// provider ID
+	ProvideChapterByID(
+		ctx context.Context,
+		id, chapter string,
+	) (*standard.ComicInfoChapter, error)
+}
+
+// ComicInfoSeriesProvider is implemented by providers that know about the
+// series as a whole, e.g. its status or the total number of chapters
+type ComicInfoSeriesProvider interface {
+	ProvideSeries(
+		ctx context.Context,
+		series string,
+	) (*standard.ComicInfoSeries, error)
 }
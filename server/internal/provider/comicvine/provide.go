@@ -4,351 +4,271 @@ package comicvine
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/vyxn/yuzu/internal/pkg/req"
 	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
 	"github.com/vyxn/yuzu/internal/standard"
 )
 
-const baseURL = "http://comicvine.gamespot.com/api/volumes"
+// DefaultBaseURL is the api used by the registered provider
+const DefaultBaseURL = "https://comicvine.gamespot.com/api"
+
+const (
+	volumesPath = "/volumes"
+	volumePath  = "/volume/%s/"
+	// volumePrefix is the resource type comicvine puts before volume ids
+	volumePrefix = "4050-"
+)
 
 type ComicVineComicInfoProvider struct {
+	client *req.Client
 	apiKey string
 }
 
-func NewComicVineProvider(apiKey string) *ComicVineComicInfoProvider {
-	return &ComicVineComicInfoProvider{apiKey}
+func init() {
+	provider.Register(provider.Registration{
+		Name:        "comicvine",
+		Credentials: []string{"COMICVINE_API_KEY"},
+		Capabilities: []provider.Capability{
+			provider.CapabilityChapter,
+			provider.CapabilitySearch,
+			provider.CapabilityByID,
+			provider.CapabilitySeries,
+		},
+		Priority: 10,
+		New: func(
+			credentials map[string]string,
+		) (provider.ComicInfoProvider, error) {
+			return NewComicVineProvider(
+				nil,
+				credentials["COMICVINE_API_KEY"],
+			), nil
+		},
+	})
+}
+
+// NewComicVineProvider calls the api with client, nil uses DefaultBaseURL
+func NewComicVineProvider(
+	client *req.Client,
+	apiKey string,
+) *ComicVineComicInfoProvider {
+	if client == nil {
+		client = req.NewClient(DefaultBaseURL, nil)
+	}
+	// a copy so the caller's client is left as is
+	c := *client
+	c.Validate = validate
+	client = &c
+	return &ComicVineComicInfoProvider{client, apiKey}
 }
 
+func (p *ComicVineComicInfoProvider) Name() string { return "comicvine" }
+
 func (p *ComicVineComicInfoProvider) ProvideChapter(
 	ctx context.Context, series, chapter string,
 ) (*standard.ComicInfoChapter, error) {
-	u, err := url.Parse(baseURL)
+	volume, err := p.findVolume(ctx, series)
 	if err != nil {
-		return nil, yerr.WithStackf("parsing url <%s>: %w", baseURL, err)
+		return nil, err
 	}
 
-	slog.Debug("Apikey", slog.String("shh", p.apiKey))
+	return p.provideChapter(ctx, volume, chapter)
+}
 
-	q := u.Query()
-	q.Add("api_key", p.apiKey)
-	q.Add("format", "json")
-	q.Add("filter", "name:"+series)
-	u.RawQuery = q.Encode()
+func (p *ComicVineComicInfoProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	// ids are "4050-N" everywhere, the bare number is accepted too
+	n := strings.TrimPrefix(id, volumePrefix)
+	if err := provider.CheckNumericID(n); err != nil {
+		return nil, err
+	}
 
-	slog.Info("start")
-	data, err := req.Get(ctx, u.String(), nil)
+	var volume Volume
+	err := p.get(ctx, fmt.Sprintf(volumePath, volumePrefix+n), nil, &volume)
 	if err != nil {
 		return nil, err
 	}
-	slog.Info("response", slog.Any("data", string(data)))
-
-	type List struct {
-		Error                string `json:"error"`
-		Limit                int    `json:"limit"`
-		Offset               int    `json:"offset"`
-		NumberOfPageResults  int    `json:"number_of_page_results"`
-		NumberOfTotalResults int    `json:"number_of_total_results"`
-		StatusCode           int    `json:"status_code"`
-		Results              []struct {
-			Aliases         string `json:"aliases"`
-			APIDetailURL    string `json:"api_detail_url"`
-			CountOfIssues   int    `json:"count_of_issues"`
-			DateAdded       string `json:"date_added"`
-			DateLastUpdated string `json:"date_last_updated"`
-			Deck            any    `json:"deck"`
-			Description     string `json:"description"`
-			FirstIssue      struct {
-				APIDetailURL string `json:"api_detail_url"`
-				ID           int    `json:"id"`
-				Name         string `json:"name"`
-				IssueNumber  string `json:"issue_number"`
-			} `json:"first_issue"`
-			ID    int `json:"id"`
-			Image struct {
-				IconURL        string `json:"icon_url"`
-				MediumURL      string `json:"medium_url"`
-				ScreenURL      string `json:"screen_url"`
-				ScreenLargeURL string `json:"screen_large_url"`
-				SmallURL       string `json:"small_url"`
-				SuperURL       string `json:"super_url"`
-				ThumbURL       string `json:"thumb_url"`
-				TinyURL        string `json:"tiny_url"`
-				OriginalURL    string `json:"original_url"`
-				ImageTags      string `json:"image_tags"`
-			} `json:"image"`
-			LastIssue struct {
-				APIDetailURL string `json:"api_detail_url"`
-				ID           int    `json:"id"`
-				Name         string `json:"name"`
-				IssueNumber  string `json:"issue_number"`
-			} `json:"last_issue"`
-			Name      string `json:"name"`
-			Publisher struct {
-				APIDetailURL string `json:"api_detail_url"`
-				ID           int    `json:"id"`
-				Name         string `json:"name"`
-			} `json:"publisher"`
-			SiteDetailURL string `json:"site_detail_url"`
-			StartYear     string `json:"start_year"`
-		} `json:"results"`
-		Version string `json:"version"`
-	}
-	var list List
-	if err = json.Unmarshal(data, &list); err != nil {
-		return nil, yerr.WithStackf("unmarshaling json response: %w", err)
-	}
 
-	if list.StatusCode != 1 {
-		return nil, yerr.WithStackf("comicvine response: %s", list.Error)
+	return p.provideChapter(ctx, &volume, chapter)
+}
+
+func (p *ComicVineComicInfoProvider) provideChapter(
+	ctx context.Context,
+	volume *Volume,
+	chapter string,
+) (*standard.ComicInfoChapter, error) {
+	ci := &standard.ComicInfoChapter{
+		Title:      "",
+		ProviderID: volumeID(volume.Results.ID),
 	}
+	standard.GetTitlePreference().Apply(ci, titles(volume))
 
-	for n, e := range list.Results {
-		if n == 0 {
+	for _, issue := range volume.Results.Issues {
+		if issue.IssueNumber != chapter {
 			continue
 		}
-		u, err := url.Parse(e.APIDetailURL)
-		if err != nil {
-			return nil, yerr.WithStackf("building url <%s>: %w", e.APIDetailURL, err)
+
+		var res Issue
+		if err := p.get(ctx, issue.APIDetailURL, nil, &res); err != nil {
+			return nil, err
 		}
 
-		q := u.Query()
-		q.Add("api_key", p.apiKey)
-		q.Add("format", "json")
-		u.RawQuery = q.Encode()
+		ci.ProviderID = fmt.Sprintf("4000-%d", res.Results.ID)
+		ci.Title = res.Results.Name
+		ci.Number = res.Results.IssueNumber
+		ci.Summary = res.Results.Description
 
-		data, err := req.Get(ctx, u.String(), nil)
-		if err != nil {
-			return nil, err
+		break
+	}
+
+	return ci, nil
+}
+
+func (p *ComicVineComicInfoProvider) ProvideSeries(
+	ctx context.Context, series string,
+) (*standard.ComicInfoSeries, error) {
+	volume, err := p.findVolume(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	v := volume.Results
+	cs := &standard.ComicInfoSeries{
+		Summary:    v.Description,
+		Count:      v.CountOfIssues,
+		Publisher:  v.Publisher.Name,
+		CoverURL:   v.Image.OriginalURL,
+		Web:        v.SiteDetailURL,
+		ProviderID: volumeID(v.ID),
+	}
+	cs.Year, _ = strconv.Atoi(v.StartYear)
+	standard.GetTitlePreference().ApplySeries(cs, titles(volume))
+
+	return cs, nil
+}
+
+// volumeID is the id of a volume as the api writes it, e.g. "4050-48989"
+func volumeID(id int) string {
+	return volumePrefix + strconv.Itoa(id)
+}
+
+// titles lists the volume name and its aliases, comicvine only has english
+// names and keeps one alias per line
+func titles(volume *Volume) []standard.Title {
+	titles := []standard.Title{{Lang: "en", Value: volume.Results.Name}}
+	if aliases, ok := volume.Results.Aliases.(string); ok {
+		for a := range strings.Lines(aliases) {
+			titles = append(titles, standard.Title{Value: strings.TrimSpace(a)})
 		}
+	}
+	return titles
+}
+
+func (p *ComicVineComicInfoProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	var list VolumeList
+	err := p.get(ctx, volumesPath, url.Values{"filter": {"name:" + series}}, &list)
+	if err != nil {
+		return nil, err
+	}
 
-		type MangaInfo struct {
-			Error                string `json:"error"`
-			Limit                int    `json:"limit"`
-			Offset               int    `json:"offset"`
-			NumberOfPageResults  int    `json:"number_of_page_results"`
-			NumberOfTotalResults int    `json:"number_of_total_results"`
-			StatusCode           int    `json:"status_code"`
-			Results              struct {
-				Aliases      any    `json:"aliases"`
-				APIDetailURL string `json:"api_detail_url"`
-				Characters   []struct {
-					APIDetailURL  string `json:"api_detail_url"`
-					ID            int    `json:"id"`
-					Name          string `json:"name"`
-					SiteDetailURL string `json:"site_detail_url"`
-					Count         string `json:"count"`
-				} `json:"characters"`
-				Concepts []struct {
-					APIDetailURL  string `json:"api_detail_url"`
-					ID            int    `json:"id"`
-					Name          string `json:"name"`
-					SiteDetailURL string `json:"site_detail_url"`
-					Count         string `json:"count"`
-				} `json:"concepts"`
-				CountOfIssues   int    `json:"count_of_issues"`
-				DateAdded       string `json:"date_added"`
-				DateLastUpdated string `json:"date_last_updated"`
-				Deck            any    `json:"deck"`
-				Description     string `json:"description"`
-				FirstIssue      struct {
-					APIDetailURL string `json:"api_detail_url"`
-					ID           int    `json:"id"`
-					Name         string `json:"name"`
-					IssueNumber  string `json:"issue_number"`
-				} `json:"first_issue"`
-				ID    int `json:"id"`
-				Image struct {
-					IconURL        string `json:"icon_url"`
-					MediumURL      string `json:"medium_url"`
-					ScreenURL      string `json:"screen_url"`
-					ScreenLargeURL string `json:"screen_large_url"`
-					SmallURL       string `json:"small_url"`
-					SuperURL       string `json:"super_url"`
-					ThumbURL       string `json:"thumb_url"`
-					TinyURL        string `json:"tiny_url"`
-					OriginalURL    string `json:"original_url"`
-					ImageTags      string `json:"image_tags"`
-				} `json:"image"`
-				Issues []struct {
-					APIDetailURL  string `json:"api_detail_url"`
-					ID            int    `json:"id"`
-					Name          string `json:"name"`
-					SiteDetailURL string `json:"site_detail_url"`
-					IssueNumber   string `json:"issue_number"`
-				} `json:"issues"`
-				LastIssue struct {
-					APIDetailURL string `json:"api_detail_url"`
-					ID           int    `json:"id"`
-					Name         string `json:"name"`
-					IssueNumber  string `json:"issue_number"`
-				} `json:"last_issue"`
-				Locations []struct {
-					APIDetailURL  string `json:"api_detail_url"`
-					ID            int    `json:"id"`
-					Name          string `json:"name"`
-					SiteDetailURL string `json:"site_detail_url"`
-					Count         string `json:"count"`
-				} `json:"locations"`
-				Name    string `json:"name"`
-				Objects []struct {
-					APIDetailURL  string `json:"api_detail_url"`
-					ID            int    `json:"id"`
-					Name          string `json:"name"`
-					SiteDetailURL string `json:"site_detail_url"`
-					Count         string `json:"count"`
-				} `json:"objects"`
-				People []struct {
-					APIDetailURL  string `json:"api_detail_url"`
-					ID            int    `json:"id"`
-					Name          string `json:"name"`
-					SiteDetailURL string `json:"site_detail_url"`
-					Count         string `json:"count"`
-				} `json:"people"`
-				Publisher struct {
-					APIDetailURL string `json:"api_detail_url"`
-					ID           int    `json:"id"`
-					Name         string `json:"name"`
-				} `json:"publisher"`
-				SiteDetailURL string `json:"site_detail_url"`
-				StartYear     string `json:"start_year"`
-			} `json:"results"`
-			Version string `json:"version"`
+	candidates := make([]provider.SeriesCandidate, 0, len(list.Results))
+	for _, v := range list.Results {
+		c := provider.SeriesCandidate{
+			ProviderID: volumeID(v.ID),
+			Titles:     []standard.Title{{Lang: "en", Value: v.Name}},
+			Type:       "comic",
+			CoverURL:   v.Image.OriginalURL,
 		}
-		var manga MangaInfo
-		if err := json.Unmarshal(data, &manga); err != nil {
-			return nil, yerr.WithStackf("unmarshaling json response: %w", err)
+		for a := range strings.Lines(v.Aliases) {
+			c.Titles = append(c.Titles, standard.Title{Value: strings.TrimSpace(a)})
 		}
+		c.Year, _ = strconv.Atoi(v.StartYear)
+		candidates = append(candidates, c)
+	}
 
-		ci := &standard.ComicInfoChapter{
-			Title:  "",
-			Series: manga.Results.Name,
-		}
+	return provider.Rank(series, candidates), nil
+}
+
+// findVolume fetches the details of the best search match for series
+func (p *ComicVineComicInfoProvider) findVolume(
+	ctx context.Context,
+	series string,
+) (*Volume, error) {
+	candidates, err := p.SearchSeries(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+	best, err := provider.Best(series, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var volume Volume
+	err = p.get(ctx, fmt.Sprintf(volumePath, best.ProviderID), nil, &volume)
+	if err != nil {
+		return nil, err
+	}
 
-		for _, issue := range manga.Results.Issues {
-			if issue.IssueNumber != chapter {
-				continue
-			}
-			u, err := url.Parse(issue.APIDetailURL)
-			if err != nil {
-				return nil, yerr.WithStackf(
-					"building url <%s>: %w",
-					issue.APIDetailURL,
-					err,
-				)
-			}
-
-			u.RawQuery = q.Encode()
-			data, err := req.Get(ctx, u.String(), nil)
-			if err != nil {
-				return nil, err
-			}
-
-			type Issue struct {
-				Error                string `json:"error"`
-				Limit                int    `json:"limit"`
-				Offset               int    `json:"offset"`
-				NumberOfPageResults  int    `json:"number_of_page_results"`
-				NumberOfTotalResults int    `json:"number_of_total_results"`
-				StatusCode           int    `json:"status_code"`
-				Results              struct {
-					Aliases          any    `json:"aliases"`
-					APIDetailURL     string `json:"api_detail_url"`
-					AssociatedImages []any  `json:"associated_images"`
-					CharacterCredits []struct {
-						APIDetailURL  string `json:"api_detail_url"`
-						ID            int    `json:"id"`
-						Name          string `json:"name"`
-						SiteDetailURL string `json:"site_detail_url"`
-					} `json:"character_credits"`
-					CharacterDiedIn []any `json:"character_died_in"`
-					ConceptCredits  []struct {
-						APIDetailURL  string `json:"api_detail_url"`
-						ID            int    `json:"id"`
-						Name          string `json:"name"`
-						SiteDetailURL string `json:"site_detail_url"`
-					} `json:"concept_credits"`
-					CoverDate                 string `json:"cover_date"`
-					DateAdded                 string `json:"date_added"`
-					DateLastUpdated           string `json:"date_last_updated"`
-					Deck                      any    `json:"deck"`
-					Description               string `json:"description"`
-					FirstAppearanceCharacters any    `json:"first_appearance_characters"`
-					FirstAppearanceConcepts   any    `json:"first_appearance_concepts"`
-					FirstAppearanceLocations  any    `json:"first_appearance_locations"`
-					FirstAppearanceObjects    any    `json:"first_appearance_objects"`
-					FirstAppearanceStoryarcs  any    `json:"first_appearance_storyarcs"`
-					FirstAppearanceTeams      any    `json:"first_appearance_teams"`
-					HasStaffReview            bool   `json:"has_staff_review"`
-					ID                        int    `json:"id"`
-					Image                     struct {
-						IconURL        string `json:"icon_url"`
-						MediumURL      string `json:"medium_url"`
-						ScreenURL      string `json:"screen_url"`
-						ScreenLargeURL string `json:"screen_large_url"`
-						SmallURL       string `json:"small_url"`
-						SuperURL       string `json:"super_url"`
-						ThumbURL       string `json:"thumb_url"`
-						TinyURL        string `json:"tiny_url"`
-						OriginalURL    string `json:"original_url"`
-						ImageTags      string `json:"image_tags"`
-					} `json:"image"`
-					IssueNumber     string `json:"issue_number"`
-					LocationCredits []struct {
-						APIDetailURL  string `json:"api_detail_url"`
-						ID            int    `json:"id"`
-						Name          string `json:"name"`
-						SiteDetailURL string `json:"site_detail_url"`
-					} `json:"location_credits"`
-					Name          string `json:"name"`
-					ObjectCredits []struct {
-						APIDetailURL  string `json:"api_detail_url"`
-						ID            int    `json:"id"`
-						Name          string `json:"name"`
-						SiteDetailURL string `json:"site_detail_url"`
-					} `json:"object_credits"`
-					PersonCredits []struct {
-						APIDetailURL  string `json:"api_detail_url"`
-						ID            int    `json:"id"`
-						Name          string `json:"name"`
-						SiteDetailURL string `json:"site_detail_url"`
-						Role          string `json:"role"`
-					} `json:"person_credits"`
-					SiteDetailURL   string `json:"site_detail_url"`
-					StoreDate       string `json:"store_date"`
-					StoryArcCredits []any  `json:"story_arc_credits"`
-					TeamCredits     []struct {
-						APIDetailURL  string `json:"api_detail_url"`
-						ID            int    `json:"id"`
-						Name          string `json:"name"`
-						SiteDetailURL string `json:"site_detail_url"`
-					} `json:"team_credits"`
-					TeamDisbandedIn []any `json:"team_disbanded_in"`
-					Volume          struct {
-						APIDetailURL  string `json:"api_detail_url"`
-						ID            int    `json:"id"`
-						Name          string `json:"name"`
-						SiteDetailURL string `json:"site_detail_url"`
-					} `json:"volume"`
-				} `json:"results"`
-				Version string `json:"version"`
-			}
-			var issue Issue
-			if err := json.Unmarshal(data, &issue); err != nil {
-				return nil, yerr.WithStackf("unmarshaling json response: %w", err)
-			}
-
-			ci.Title = issue.Results.Name
-			ci.Number = issue.Results.IssueNumber
-			ci.Summary = issue.Results.Description
-
-			break
+	return &volume, nil
+}
+
+// get calls a comicvine api path, or the absolute urls found in responses,
+// with the api key and decodes the json response into res
+func (p *ComicVineComicInfoProvider) get(
+	ctx context.Context,
+	apiURL string,
+	params url.Values,
+	res any,
+) error {
+	u, err := url.Parse(p.client.URL(apiURL))
+	if err != nil {
+		return yerr.WithStackf("building url <%s>: %w", apiURL, err)
+	}
+
+	q := u.Query()
+	for k, vs := range params {
+		for _, v := range vs {
+			q.Add(k, v)
 		}
+	}
+	q.Add("api_key", p.apiKey)
+	q.Add("format", "json")
+	u.RawQuery = q.Encode()
 
-		return ci, nil
+	data, err := p.client.Get(ctx, u.String(), nil)
+	if err != nil {
+		return err
 	}
+	slog.Debug("response", slog.Any("data", string(data)))
 
-	return nil, yerr.WithStackf("not found")
+	if err := json.Unmarshal(data, res); err != nil {
+		return yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+
+	return nil
+}
+
+// validate fails responses whose status_code isn't 1, comicvine reports
+// errors such as a bad api key with a 200 status
+func validate(data []byte) error {
+	var status struct {
+		Error      string `json:"error"`
+		StatusCode int    `json:"status_code"`
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+	if status.StatusCode != 1 {
+		return yerr.WithStackf("comicvine response: %s", status.Error)
+	}
+	return nil
 }
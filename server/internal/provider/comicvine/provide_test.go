@@ -0,0 +1,132 @@
+package comicvine
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/cache"
+	"github.com/vyxn/yuzu/internal/pkg/dbtest"
+	"github.com/vyxn/yuzu/internal/pkg/req/reqtest"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// newProvider replays the fixtures, the api key only matters with -record
+func newProvider(t *testing.T) *ComicVineComicInfoProvider {
+	return NewComicVineProvider(
+		reqtest.Client(t, "testdata", DefaultBaseURL),
+		cmp.Or(os.Getenv("COMICVINE_API_KEY"), "test"),
+	)
+}
+
+func TestProvideChapter(t *testing.T) {
+	got, err := newProvider(t).ProvideChapter(context.Background(), "Saga", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &standard.ComicInfoChapter{
+		Title:           "Chapter Two",
+		Series:          "Saga",
+		Number:          "2",
+		AlternateSeries: "Saga (2012)",
+		Summary:         "<p>Marko and Alana flee the planet Cleave.</p>",
+		LanguageISO:     "en",
+		ProviderID:      "4000-340122",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProvideChapter() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestProvideSeries(t *testing.T) {
+	got, err := newProvider(t).ProvideSeries(context.Background(), "Saga")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Publisher != "Image" || got.Year != 2012 || got.Count != 66 ||
+		got.ProviderID != "4050-48989" {
+		t.Errorf("ProvideSeries() = %+v", got)
+	}
+}
+
+func TestProvideChapterByID(t *testing.T) {
+	p := newProvider(t)
+
+	for _, id := range []string{"48989", "4050-48989"} {
+		got, err := p.ProvideChapterByID(context.Background(), id, "2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ProviderID != "4000-340122" {
+			t.Errorf("ProvideChapterByID(%q) = %+v", id, got)
+		}
+	}
+
+	for _, id := range []string{"", "../issue/4000-340122", "4050-", "48989/"} {
+		_, err := p.ProvideChapterByID(context.Background(), id, "2")
+		if !errors.Is(err, provider.ErrNotFound) {
+			t.Errorf("ProvideChapterByID(%q) error = %v, want not found", id, err)
+		}
+	}
+}
+
+func TestIDRoundTrip(t *testing.T) {
+	p := newProvider(t)
+	ctx := context.Background()
+
+	candidates, err := p.SearchSeries(ctx, "Saga")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := p.ProvideSeries(ctx, "Saga")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{candidates[0].ProviderID, cs.ProviderID} {
+		got, err := p.ProvideChapterByID(ctx, id, "2")
+		if err != nil {
+			t.Fatalf("ProvideChapterByID(%q) = %v", id, err)
+		}
+		if got.ProviderID != "4000-340122" {
+			t.Errorf("ProvideChapterByID(%q) = %+v", id, got)
+		}
+	}
+}
+
+func TestCachedKeyHasNoAPIKey(t *testing.T) {
+	db := dbtest.Open(t)
+	rc := cache.New(db, cache.DefaultConfig())
+	p := provider.Cached(
+		NewComicVineProvider(
+			reqtest.Client(t, "testdata", DefaultBaseURL),
+			"secret-key",
+		),
+		rc,
+	)
+
+	_, err := p.ProvideChapter(context.Background(), "Saga", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{}
+	if err := db.Select(&keys, "SELECT key FROM cache"); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) == 0 {
+		t.Fatal("nothing cached")
+	}
+	for _, k := range keys {
+		if strings.Contains(k, "secret-key") || strings.Contains(k, "api_key") {
+			t.Errorf("cache key %q holds the api key", k)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"reflect"
+
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// FieldComparison lines up the value every provider gave for a field with
+// the value that was merged
+type FieldComparison struct {
+	Field string `json:"field"`
+	// Values is keyed by provider name, providers without a value are left out
+	Values  map[string]any `json:"values"`
+	Merged  any            `json:"merged,omitempty"`
+	Sources []Source       `json:"sources,omitempty"`
+	// Agree is true when every provider that has a value gave the same one
+	Agree bool `json:"agree"`
+}
+
+// Compare lays results out field by field next to merged, fields no provider
+// filled are skipped
+func Compare(
+	results []ProviderResult,
+	merged *standard.ComicInfoChapter,
+	provenance Provenance,
+) []FieldComparison {
+	out := []FieldComparison{}
+	t := reflect.TypeFor[standard.ComicInfoChapter]()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Name == "XMLName" {
+			continue
+		}
+
+		fc := FieldComparison{
+			Field:  f.Name,
+			Values: map[string]any{},
+			Agree:  true,
+		}
+		var first reflect.Value
+		for _, r := range results {
+			v := reflect.ValueOf(r.Chapter).Elem().Field(i)
+			if v.IsZero() {
+				continue
+			}
+			if first.IsValid() && !reflect.DeepEqual(
+				first.Interface(),
+				v.Interface(),
+			) {
+				fc.Agree = false
+			}
+			if !first.IsValid() {
+				first = v
+			}
+			fc.Values[r.Provider] = v.Interface()
+		}
+		if len(fc.Values) == 0 {
+			continue
+		}
+
+		if merged != nil {
+			if v := reflect.ValueOf(merged).Elem().Field(i); !v.IsZero() {
+				fc.Merged = v.Interface()
+			}
+		}
+		fc.Sources = provenance[f.Name]
+		out = append(out, fc)
+	}
+
+	return out
+}
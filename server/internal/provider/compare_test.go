@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+func TestCompare(t *testing.T) {
+	results := []ProviderResult{
+		{"kitsu", &standard.ComicInfoChapter{
+			Title: "The Brand",
+			Year:  1989,
+			Genre: standard.List{"Action"},
+		}},
+		{"anilist", &standard.ComicInfoChapter{
+			Title: "The Brand",
+			Year:  1990,
+		}},
+	}
+	merged := &standard.ComicInfoChapter{
+		Title: "The Brand",
+		Year:  1989,
+		Genre: standard.List{"Action"},
+	}
+	provenance := Provenance{"Year": {{Provider: "kitsu"}}}
+
+	got := Compare(results, merged, provenance)
+	want := []FieldComparison{
+		{
+			Field:  "Title",
+			Values: map[string]any{"kitsu": "The Brand", "anilist": "The Brand"},
+			Merged: "The Brand",
+			Agree:  true,
+		},
+		{
+			Field:   "Year",
+			Values:  map[string]any{"kitsu": 1989, "anilist": 1990},
+			Merged:  1989,
+			Sources: []Source{{Provider: "kitsu"}},
+			Agree:   false,
+		},
+		{
+			Field:  "Genre",
+			Values: map[string]any{"kitsu": standard.List{"Action"}},
+			Merged: standard.List{"Action"},
+			Agree:  true,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCompareWithoutMerged(t *testing.T) {
+	results := []ProviderResult{
+		{"kitsu", &standard.ComicInfoChapter{Title: "The Brand"}},
+	}
+
+	got := Compare(results, nil, nil)
+	if len(got) != 1 || got[0].Merged != nil || got[0].Sources != nil {
+		t.Errorf("Compare() = %+v", got)
+	}
+}
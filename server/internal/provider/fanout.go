@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// DefaultTimeout bounds a single provider call when no timeout is given
+const DefaultTimeout = 20 * time.Second
+
+// ProviderErrors maps provider names to the error their call failed with
+type ProviderErrors map[string]error
+
+// MarshalJSON writes the error messages, errors don't marshal on their own
+func (e ProviderErrors) MarshalJSON() ([]byte, error) {
+	out := make(map[string]string, len(e))
+	for name, err := range e {
+		out[name] = err.Error()
+	}
+	return json.Marshal(out)
+}
+
+// Check fails when a required provider failed, or when required is empty and
+// every provider failed
+func (e ProviderErrors) Check(required []string, succeeded int) error {
+	errs := []error{}
+	for _, name := range required {
+		if err, ok := e[name]; ok {
+			errs = append(errs, yerr.WithStackf(
+				"required provider <%s> failed: %w",
+				name,
+				err,
+			))
+		}
+	}
+	if len(required) == 0 && succeeded == 0 {
+		for _, name := range slices.Sorted(maps.Keys(e)) {
+			errs = append(errs, yerr.WithStackf(
+				"provider <%s> failed: %w",
+				name,
+				e[name],
+			))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FanOut calls fn for every provider in parallel, each call gets its own
+// timeout derived from ctx, results keep the order of providers
+func FanOut(
+	ctx context.Context,
+	timeout time.Duration,
+	providers []ComicInfoProvider,
+	fn func(context.Context, ComicInfoProvider) (*standard.ComicInfoChapter, error),
+) ([]ProviderResult, ProviderErrors) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	chapters := make([]*standard.ComicInfoChapter, len(providers))
+	errs := make([]error, len(providers))
+
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				// a panicking provider must not take the server down with it
+				if r := recover(); r != nil {
+					errs[i] = yerr.WithStackf("provider panicked: %v", r)
+				}
+			}()
+
+			pctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			chapters[i], errs[i] = fn(pctx, p)
+			if errs[i] == nil && chapters[i] == nil {
+				errs[i] = yerr.WithStackf("provider returned no chapter")
+			}
+		}()
+	}
+	wg.Wait()
+
+	results := []ProviderResult{}
+	failed := ProviderErrors{}
+	for i, p := range providers {
+		if errs[i] != nil {
+			failed[p.Name()] = errs[i]
+			continue
+		}
+		results = append(results, ProviderResult{p.Name(), chapters[i]})
+	}
+
+	return results, failed
+}
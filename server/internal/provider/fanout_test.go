@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// stubProvider answers chapter lookups with chapter
+type stubProvider struct {
+	name    string
+	chapter func(ctx context.Context) (*standard.ComicInfoChapter, error)
+}
+
+func (p stubProvider) Name() string { return p.name }
+
+func (p stubProvider) ProvideChapter(
+	ctx context.Context, series, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	return p.chapter(ctx)
+}
+
+func (p stubProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	return p.chapter(ctx)
+}
+
+func (p stubProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]SeriesCandidate, error) {
+	return nil, ErrNotFound
+}
+
+// returning makes a stub that gives ci back
+func returning(name string, ci *standard.ComicInfoChapter) stubProvider {
+	return stubProvider{
+		name,
+		func(context.Context) (*standard.ComicInfoChapter, error) {
+			return ci, nil
+		},
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	errDown := errors.New("api down")
+	providers := []ComicInfoProvider{
+		returning("first", &standard.ComicInfoChapter{Title: "First"}),
+		stubProvider{
+			"slow",
+			func(ctx context.Context) (*standard.ComicInfoChapter, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		},
+		stubProvider{
+			"panics",
+			func(context.Context) (*standard.ComicInfoChapter, error) {
+				panic("boom")
+			},
+		},
+		stubProvider{
+			"fails",
+			func(context.Context) (*standard.ComicInfoChapter, error) {
+				return nil, errDown
+			},
+		},
+		returning("empty", nil),
+		returning("last", &standard.ComicInfoChapter{Title: "Last"}),
+	}
+
+	start := time.Now()
+	results, failed := FanOut(
+		context.Background(),
+		50*time.Millisecond,
+		providers,
+		func(
+			ctx context.Context,
+			p ComicInfoProvider,
+		) (*standard.ComicInfoChapter, error) {
+			return p.ProvideChapter(ctx, "Berserk", "1")
+		},
+	)
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("FanOut() took %v, the timeout is 50ms", d)
+	}
+
+	names := []string{}
+	for _, r := range results {
+		names = append(names, r.Provider)
+	}
+	if want := []string{"first", "last"}; !slices.Equal(names, want) {
+		t.Errorf("results from %q, want %q in provider order", names, want)
+	}
+
+	if !errors.Is(failed["slow"], context.DeadlineExceeded) {
+		t.Errorf("slow error = %v, want the deadline", failed["slow"])
+	}
+	if failed["panics"] == nil {
+		t.Error("the panic wasn't reported")
+	}
+	if !errors.Is(failed["fails"], errDown) {
+		t.Errorf("fails error = %v, want %v", failed["fails"], errDown)
+	}
+	if failed["empty"] == nil {
+		t.Error("a nil chapter wasn't reported")
+	}
+	if len(failed) != 4 {
+		t.Errorf("failed = %v, want 4 providers", failed)
+	}
+}
+
+func TestProviderErrorsCheck(t *testing.T) {
+	failed := ProviderErrors{"kitsu": ErrNotFound}
+
+	tests := []struct {
+		name      string
+		required  []string
+		succeeded int
+		valid     bool
+	}{
+		{"some succeeded", nil, 1, true},
+		{"none succeeded", nil, 0, false},
+		{"required failed", []string{"kitsu"}, 2, false},
+		{"required succeeded", []string{"anilist"}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := failed.Check(tt.required, tt.succeeded)
+			if tt.valid && err != nil {
+				t.Errorf("Check() = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrNotFound) {
+				t.Errorf("Check() = %v, want the provider error", err)
+			}
+		})
+	}
+}
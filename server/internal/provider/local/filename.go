@@ -0,0 +1,163 @@
+package local
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Formats set from file name markers
+const (
+	FormatOneShot = "One-Shot"
+	FormatExtra   = "Extra"
+)
+
+// Filename is what a chapter archive name tells about the chapter
+type Filename struct {
+	Volume int
+	// Chapter is the number as written, e.g. "012.5"
+	Chapter  string
+	Year     int
+	Language string
+	Group    string
+	Format   string
+}
+
+var (
+	reBracket = regexp.MustCompile(`[\[(]([^\])]*)[\])]`)
+	reVolume  = regexp.MustCompile(`(?i)\b(?:v|vol\.?|volume)\s?(\d+)\b`)
+	reKeyword = regexp.MustCompile(
+		`(?i)(?:\b|\d)(?:c|ch\.?|chapter|chap\.?|#)\s?(\d+(?:\.\d+)?)`,
+	)
+	reNumber  = regexp.MustCompile(`\d+(?:\.\d+)?`)
+	reYear    = regexp.MustCompile(`^(?:19|20)\d\d$`)
+	reOneShot = regexp.MustCompile(`(?i)\bone[-_ ]?shot\b`)
+	reExtra   = regexp.MustCompile(`(?i)\b(?:extra|omake|special)\b`)
+)
+
+// releaseTags are bracketed tags describing the release rather than the
+// group that made it
+var releaseTags = map[string]bool{
+	"digital":  true,
+	"c2c":      true,
+	"web":      true,
+	"webrip":   true,
+	"scan":     true,
+	"official": true,
+	"complete": true,
+	"fixed":    true,
+	"hq":       true,
+	"hd":       true,
+	"lq":       true,
+	"raw":      true,
+}
+
+// languages maps language names used in file names to their code, two letter
+// codes and regional ones like "pt-BR" are parsed directly
+var languages = map[string]string{
+	"english":    "en",
+	"spanish":    "es",
+	"french":     "fr",
+	"german":     "de",
+	"italian":    "it",
+	"portuguese": "pt",
+	"brazilian":  "pt-BR",
+	"russian":    "ru",
+	"polish":     "pl",
+	"turkish":    "tr",
+	"arabic":     "ar",
+	"indonesian": "id",
+	"vietnamese": "vi",
+	"thai":       "th",
+	"japanese":   "ja",
+	"korean":     "ko",
+	"chinese":    "zh",
+}
+
+// ParseFilename reads volume, chapter, year, language, scanlation group and
+// one-shot or extra markers from a chapter archive name, bracketed parts are
+// volumes, years, languages, extra markers or, in square brackets only and
+// unless they name the release, the group
+func ParseFilename(file string) Filename {
+	name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+	var f Filename
+	extra := false
+
+	for _, m := range reBracket.FindAllStringSubmatch(name, -1) {
+		tag := strings.TrimSpace(m[1])
+		vol := reVolume.FindStringSubmatch(tag)
+		switch {
+		case tag == "":
+		case vol != nil && vol[0] == tag && f.Volume == 0:
+			f.Volume, _ = strconv.Atoi(vol[1])
+		case reYear.MatchString(tag) && f.Year == 0:
+			f.Year, _ = strconv.Atoi(tag)
+		case parseLanguage(tag) != "" && f.Language == "":
+			f.Language = parseLanguage(tag)
+		case reExtra.MatchString(tag):
+			extra = true
+		case m[0][0] == '[' && f.Group == "" && !reNumber.MatchString(tag) &&
+			!releaseTags[strings.ToLower(tag)]:
+			f.Group = tag
+		}
+	}
+	rest := reBracket.ReplaceAllString(name, " ")
+
+	if m := reVolume.FindStringSubmatch(rest); m != nil {
+		f.Volume, _ = strconv.Atoi(m[1])
+		rest = strings.Replace(rest, m[0], " ", 1)
+	}
+
+	// markers before the chapter number are part of the series name
+	end := -1
+	if m := reKeyword.FindStringSubmatchIndex(rest); m != nil {
+		f.Chapter, end = rest[m[2]:m[3]], m[3]
+	} else if n := reNumber.FindAllStringIndex(rest, -1); len(n) > 0 {
+		// series names may hold numbers too, the chapter is usually last
+		last := n[len(n)-1]
+		f.Chapter, end = rest[last[0]:last[1]], last[1]
+	}
+	extra = extra || end >= 0 && reExtra.MatchString(rest[end:])
+
+	switch {
+	case reOneShot.MatchString(rest):
+		f.Format = FormatOneShot
+		if f.Chapter == "" {
+			f.Chapter = "1"
+		}
+	case extra:
+		f.Format = FormatExtra
+	}
+
+	return f
+}
+
+// Number is Chapter without leading zeros, as ComicInfo expects it
+func (f Filename) Number() string {
+	if f.Chapter == "" {
+		return ""
+	}
+	n := strings.TrimLeft(f.Chapter, "0")
+	if n == "" || n[0] == '.' {
+		n = "0" + n
+	}
+	return n
+}
+
+func parseLanguage(tag string) string {
+	if code, ok := languages[strings.ToLower(tag)]; ok {
+		return code
+	}
+	// longer codes are too easily mistaken for group names
+	if len(tag) != 2 && (len(tag) != 5 || tag[2] != '-') {
+		return ""
+	}
+	t, err := language.Parse(tag)
+	if err != nil {
+		return ""
+	}
+	return t.String()
+}
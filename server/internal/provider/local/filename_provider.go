@@ -0,0 +1,110 @@
+package local
+
+import (
+	"context"
+	"path"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+func init() {
+	provider.Register(provider.Registration{
+		Name: "filename",
+		Capabilities: []provider.Capability{
+			provider.CapabilityChapter,
+			provider.CapabilitySearch,
+			provider.CapabilityByID,
+		},
+		// the file name states facts about the file at hand, they beat both
+		// stored metadata and upstream guesses
+		Priority: 0,
+		New: func(map[string]string) (provider.ComicInfoProvider, error) {
+			return NewFilenameProvider(Root()), nil
+		},
+	})
+}
+
+// FilenameComicInfoProvider reads chapter metadata from archive names
+type FilenameComicInfoProvider struct {
+	library *LocalComicInfoProvider
+}
+
+// NewFilenameProvider looks the archives up in the series folders of root
+func NewFilenameProvider(root string) *FilenameComicInfoProvider {
+	return &FilenameComicInfoProvider{NewLocalProvider(root)}
+}
+
+func (p *FilenameComicInfoProvider) Name() string { return "filename" }
+
+// ProvideChapter parses chapter directly when it is an archive name,
+// otherwise the archive of chapter is looked up in the series folder
+func (p *FilenameComicInfoProvider) ProvideChapter(
+	ctx context.Context, series, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	if path.Ext(chapter) == ".cbz" {
+		return parseChapter(chapter), nil
+	}
+
+	dir, err := p.library.findSeries(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.provideChapter(dir, chapter)
+}
+
+func (p *FilenameComicInfoProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	if !isFolderName(id) {
+		return nil, yerr.WithStackf(
+			"invalid series folder <%s>: %w",
+			id,
+			provider.ErrNotFound,
+		)
+	}
+
+	return p.provideChapter(id, chapter)
+}
+
+func (p *FilenameComicInfoProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	return p.library.SearchSeries(ctx, series)
+}
+
+func (p *FilenameComicInfoProvider) provideChapter(
+	dir, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	_, archive, err := p.library.files(dir, chapter)
+	if err != nil {
+		return nil, err
+	}
+	if archive == "" {
+		return nil, yerr.WithStackf(
+			"no archive for chapter <%s> of <%s>: %w",
+			chapter,
+			dir,
+			provider.ErrNotFound,
+		)
+	}
+
+	ci := parseChapter(archive)
+	ci.ProviderID = path.Join(dir, archive)
+	return ci, nil
+}
+
+func parseChapter(file string) *standard.ComicInfoChapter {
+	f := ParseFilename(file)
+	return &standard.ComicInfoChapter{
+		Number:          f.Number(),
+		Volume:          f.Volume,
+		Year:            f.Year,
+		LanguageISO:     f.Language,
+		ScanInformation: f.Group,
+		Format:          f.Format,
+		ProviderID:      path.Base(file),
+	}
+}
@@ -0,0 +1,64 @@
+package local
+
+import "testing"
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		file string
+		want Filename
+	}{
+		{"Berserk 001.cbz", Filename{Chapter: "001"}},
+		{
+			"Berserk v01 c012.5 (2003) [English] [Group].cbz",
+			Filename{
+				Volume:   1,
+				Chapter:  "012.5",
+				Year:     2003,
+				Language: "en",
+				Group:    "Group",
+			},
+		},
+		{"Berserk (v01) c003.cbz", Filename{Volume: 1, Chapter: "003"}},
+		{"Berserk [v02] 004.cbz", Filename{Volume: 2, Chapter: "004"}},
+		{"Berserk 005 [Digital].cbz", Filename{Chapter: "005"}},
+		{
+			"Berserk 006 [Digital] [Group].cbz",
+			Filename{Chapter: "006", Group: "Group"},
+		},
+		{"Saga 007 (c2c) [pt-BR].cbz", Filename{Chapter: "007", Language: "pt-BR"}},
+		{"Extra Ordinary 5.cbz", Filename{Chapter: "5"}},
+		{"Special A 01.cbz", Filename{Chapter: "01"}},
+		{"Berserk 012 Extra.cbz", Filename{Chapter: "012", Format: FormatExtra}},
+		{"Berserk 013 (Omake).cbz", Filename{Chapter: "013", Format: FormatExtra}},
+		{
+			"Special A 02 [Special].cbz",
+			Filename{Chapter: "02", Format: FormatExtra},
+		},
+		{"Berserk Oneshot.cbz", Filename{Chapter: "1", Format: FormatOneShot}},
+		{"Berserk - Chapter 3.cbz", Filename{Chapter: "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := ParseFilename(tt.file); got != tt.want {
+				t.Errorf("ParseFilename() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilenameNumber(t *testing.T) {
+	tests := []struct {
+		chapter, want string
+	}{
+		{"001", "1"},
+		{"012.5", "12.5"},
+		{"000", "0"},
+		{"0.5", "0.5"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := (Filename{Chapter: tt.chapter}).Number(); got != tt.want {
+			t.Errorf("Number(%q) = %q, want %q", tt.chapter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package local
+
+import (
+	"cmp"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DefaultRoot is the library directory used when LIBRARY_DIR isn't set
+const DefaultRoot = "testlib"
+
+// Root returns the library directory, one folder per series holding the
+// chapter archives
+func Root() string {
+	return cmp.Or(os.Getenv("LIBRARY_DIR"), DefaultRoot)
+}
+
+// ChapterNumber extracts the chapter number of a cbz file name as written
+func ChapterNumber(file string) (string, bool) {
+	if path.Ext(file) != ".cbz" {
+		return "", false
+	}
+	f := ParseFilename(file)
+	return f.Chapter, f.Chapter != ""
+}
+
+// sameChapter compares chapter numbers ignoring leading zeros
+func sameChapter(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	na, errA := strconv.ParseFloat(a, 64)
+	nb, errB := strconv.ParseFloat(b, 64)
+	return errA == nil && errB == nil && na == nb
+}
@@ -0,0 +1,259 @@
+// Package local implements the provider interface for metadata already
+// stored in the library, so re-scans keep it when upstream apis are down
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/provider/match"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// sidecarSuffix is appended to the chapter number by lib when it writes the
+// ComicInfo next to the archive
+const sidecarSuffix = "." + standard.ComicInfoFilename
+
+func init() {
+	provider.Register(provider.Registration{
+		Name: "local",
+		Capabilities: []provider.Capability{
+			provider.CapabilityChapter,
+			provider.CapabilitySearch,
+			provider.CapabilityByID,
+			provider.CapabilitySeries,
+		},
+		// the stored metadata is mostly what yuzu wrote earlier, it only
+		// fills in for the apis that fail or don't know a field
+		Priority: 100,
+		New: func(map[string]string) (provider.ComicInfoProvider, error) {
+			return NewLocalProvider(Root()), nil
+		},
+	})
+}
+
+type LocalComicInfoProvider struct {
+	root string
+}
+
+// NewLocalProvider reads the metadata of the series folders inside root
+func NewLocalProvider(root string) *LocalComicInfoProvider {
+	return &LocalComicInfoProvider{root}
+}
+
+func (p *LocalComicInfoProvider) Name() string { return "local" }
+
+func (p *LocalComicInfoProvider) ProvideChapter(
+	ctx context.Context, series, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	dir, err := p.findSeries(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.provideChapter(dir, chapter)
+}
+
+func (p *LocalComicInfoProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	if !isFolderName(id) {
+		return nil, yerr.WithStackf(
+			"invalid series folder <%s>: %w",
+			id,
+			provider.ErrNotFound,
+		)
+	}
+
+	return p.provideChapter(id, chapter)
+}
+
+func (p *LocalComicInfoProvider) ProvideSeries(
+	ctx context.Context, series string,
+) (*standard.ComicInfoSeries, error) {
+	dir, err := p.findSeries(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	cs, err := standard.ReadSeriesJSON(
+		path.Join(p.root, dir, standard.SeriesJSONFilename),
+	)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, yerr.WithStackf(
+			"no series.json in <%s>: %w",
+			dir,
+			provider.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cs.ProviderID = dir
+
+	return cs, nil
+}
+
+// SearchSeries ranks the series folders of the library
+func (p *LocalComicInfoProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	entries, err := os.ReadDir(p.root)
+	if err != nil {
+		return nil, yerr.WithStackf("reading library <%s>: %w", p.root, err)
+	}
+
+	candidates := []provider.SeriesCandidate{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		candidates = append(candidates, provider.SeriesCandidate{
+			ProviderID: e.Name(),
+			Titles:     []standard.Title{{Value: e.Name()}},
+		})
+	}
+
+	return provider.Rank(series, candidates), nil
+}
+
+// findSeries returns the folder holding series, fuzzy matches could attach
+// the metadata of another series so the normalized names must be equal
+func (p *LocalComicInfoProvider) findSeries(
+	ctx context.Context,
+	series string,
+) (string, error) {
+	if isFolderName(series) {
+		info, err := os.Stat(path.Join(p.root, series))
+		if err == nil && info.IsDir() {
+			return series, nil
+		}
+	}
+
+	entries, err := os.ReadDir(p.root)
+	if err != nil {
+		return "", yerr.WithStackf("reading library <%s>: %w", p.root, err)
+	}
+	want := match.Normalize(series)
+	for _, e := range entries {
+		if e.IsDir() && want != "" && match.Normalize(e.Name()) == want {
+			return e.Name(), nil
+		}
+	}
+
+	return "", yerr.WithStackf(
+		"no series folder <%s>: %w",
+		series,
+		provider.ErrNotFound,
+	)
+}
+
+// provideChapter reads the sidecar of chapter, or the ComicInfo.xml inside
+// its archive, and fills the gaps from series.json
+func (p *LocalComicInfoProvider) provideChapter(
+	dir, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	full := path.Join(p.root, dir)
+	sidecar, archive, err := p.files(dir, chapter)
+	if err != nil {
+		return nil, err
+	}
+
+	var ci *standard.ComicInfoChapter
+	switch {
+	case sidecar != "":
+		ci, err = standard.ReadComicInfoFile(path.Join(full, sidecar))
+		if ci != nil {
+			ci.ProviderID = path.Join(dir, sidecar)
+		}
+	case archive != "":
+		ci, err = standard.ReadComicInfoCBZ(path.Join(full, archive))
+		if ci != nil {
+			ci.ProviderID = path.Join(dir, archive)
+		}
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	cs, err := standard.ReadSeriesJSON(
+		path.Join(full, standard.SeriesJSONFilename),
+	)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if ci == nil && cs == nil {
+		return nil, yerr.WithStackf(
+			"no local metadata for chapter <%s> of <%s>: %w",
+			chapter,
+			dir,
+			provider.ErrNotFound,
+		)
+	}
+	if ci == nil {
+		ci = &standard.ComicInfoChapter{
+			ProviderID: path.Join(dir, standard.SeriesJSONFilename),
+		}
+	}
+	if cs != nil {
+		fromSeries := &standard.ComicInfoChapter{
+			Series:    cs.Series,
+			Summary:   cs.Summary,
+			Count:     cs.Count,
+			Publisher: cs.Publisher,
+			Imprint:   cs.Imprint,
+			AgeRating: cs.AgeRating,
+		}
+		provider.MergeStructs(ci, fromSeries)
+	}
+
+	return ci, nil
+}
+
+// files returns the names of the sidecar and the archive of chapter inside
+// the series folder dir, either may be empty
+func (p *LocalComicInfoProvider) files(
+	dir, chapter string,
+) (sidecar, archive string, err error) {
+	full := path.Join(p.root, dir)
+	entries, err := os.ReadDir(full)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", yerr.WithStackf(
+			"no series folder <%s>: %w",
+			dir,
+			provider.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return "", "", yerr.WithStackf("reading series <%s>: %w", full, err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if n, ok := strings.CutSuffix(name, sidecarSuffix); ok &&
+			sameChapter(n, chapter) {
+			sidecar = name
+		}
+		if n, ok := ChapterNumber(name); ok && sameChapter(n, chapter) &&
+			archive == "" {
+			archive = name
+		}
+	}
+
+	return sidecar, archive, nil
+}
+
+// isFolderName is true when name is a single folder inside the library
+func isFolderName(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		!strings.ContainsAny(name, `/\`)
+}
@@ -0,0 +1,92 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/provider"
+)
+
+func TestFindSeries(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"Berserk", "Berserk of Gluttony", "Kaguya-sama (2016)"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := NewLocalProvider(root)
+
+	tests := []struct {
+		series, want string
+	}{
+		{"Berserk", "Berserk"},
+		{"BERSERK", "Berserk"},
+		{"Kaguya sama", "Kaguya-sama (2016)"},
+		{"Berserker", ""},
+		{"Berserk of", ""},
+		{"Gluttony", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := p.findSeries(context.Background(), tt.series)
+		if tt.want == "" {
+			if !errors.Is(err, provider.ErrNotFound) {
+				t.Errorf("findSeries(%q) = %q, %v, want not found", tt.series, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("findSeries(%q) = %q, %v, want %q", tt.series, got, err, tt.want)
+		}
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "Berserk"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	lp := NewLocalProvider(root)
+	fp := NewFilenameProvider(root)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"unknown series", func() error {
+			_, err := lp.ProvideChapter(ctx, "Vagabond", "1")
+			return err
+		}},
+		{"chapter without metadata", func() error {
+			_, err := lp.ProvideChapter(ctx, "Berserk", "1")
+			return err
+		}},
+		{"series without series.json", func() error {
+			_, err := lp.ProvideSeries(ctx, "Berserk")
+			return err
+		}},
+		{"unknown folder id", func() error {
+			_, err := lp.ProvideChapterByID(ctx, "Vagabond", "1")
+			return err
+		}},
+		{"invalid folder id", func() error {
+			_, err := lp.ProvideChapterByID(ctx, "../Berserk", "1")
+			return err
+		}},
+		{"chapter without archive", func() error {
+			_, err := fp.ProvideChapter(ctx, "Berserk", "1")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, provider.ErrNotFound) {
+				t.Errorf("error = %v, want not found", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+// Package mangadex implements the provider interface for mangadex metadata
+package mangadex
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vyxn/yuzu/internal/pkg/req"
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// DefaultBaseURL is the api used by the registered provider
+const DefaultBaseURL = "https://api.mangadex.org"
+
+const coverURL = "https://uploads.mangadex.org/covers/%s/%s"
+
+// DefaultLanguage is the chapter translation looked up when
+// MANGADEX_LANGUAGE isn't set
+const DefaultLanguage = "en"
+
+func init() {
+	provider.Register(provider.Registration{
+		Name: "mangadex",
+		Capabilities: []provider.Capability{
+			provider.CapabilityChapter,
+			provider.CapabilitySearch,
+			provider.CapabilityByID,
+			provider.CapabilitySeries,
+		},
+		Priority: 15,
+		New: func(map[string]string) (provider.ComicInfoProvider, error) {
+			return NewMangaDexProvider(
+				nil,
+				os.Getenv("MANGADEX_LANGUAGE"),
+			), nil
+		},
+	})
+}
+
+type MangaDexComicInfoProvider struct {
+	client   *req.Client
+	language string
+}
+
+// NewMangaDexProvider looks chapters up in the given translated language,
+// a nil client uses DefaultBaseURL
+func NewMangaDexProvider(
+	client *req.Client,
+	language string,
+) *MangaDexComicInfoProvider {
+	if client == nil {
+		client = req.NewClient(DefaultBaseURL, nil)
+	}
+	// a copy so the caller's client is left as is
+	c := *client
+	c.Validate = validate
+	client = &c
+	return &MangaDexComicInfoProvider{client, cmp.Or(language, DefaultLanguage)}
+}
+
+func (p *MangaDexComicInfoProvider) Name() string { return "mangadex" }
+
+func (p *MangaDexComicInfoProvider) ProvideChapter(
+	ctx context.Context, series, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	manga, err := p.findManga(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get mangadex comicinfo: %w", err)
+	}
+
+	return p.provideChapter(ctx, manga, chapter)
+}
+
+func (p *MangaDexComicInfoProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	manga, err := p.manga(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get mangadex comicinfo: %w", err)
+	}
+
+	return p.provideChapter(ctx, manga, chapter)
+}
+
+func (p *MangaDexComicInfoProvider) ProvideSeries(
+	ctx context.Context, series string,
+) (*standard.ComicInfoSeries, error) {
+	manga, err := p.findManga(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get mangadex series info: %w", err)
+	}
+
+	return parseSeries(manga), nil
+}
+
+func (p *MangaDexComicInfoProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	var list MangaList
+	err := p.get(ctx, "/manga", url.Values{
+		"title":      {series},
+		"limit":      {"10"},
+		"includes[]": {"cover_art"},
+	}, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]provider.SeriesCandidate, 0, len(list.Data))
+	for _, m := range list.Data {
+		candidates = append(candidates, provider.SeriesCandidate{
+			ProviderID: m.ID,
+			Titles:     titles(&m),
+			Year:       m.Attributes.Year,
+			Type:       format(&m),
+			CoverURL:   cover(&m),
+		})
+	}
+
+	return provider.Rank(series, candidates), nil
+}
+
+// findManga fetches the details of the best search match for series
+func (p *MangaDexComicInfoProvider) findManga(
+	ctx context.Context,
+	series string,
+) (*Manga, error) {
+	candidates, err := p.SearchSeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("finding series: %w", err)
+	}
+	best, err := provider.Best(series, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("finding series: %w", err)
+	}
+
+	return p.manga(ctx, best.ProviderID)
+}
+
+func (p *MangaDexComicInfoProvider) manga(
+	ctx context.Context,
+	id string,
+) (*Manga, error) {
+	var res MangaEntity
+	err := p.get(ctx, "/manga/"+url.PathEscape(id), url.Values{
+		"includes[]": {"author", "artist", "cover_art"},
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Data, nil
+}
+
+// chapter returns the earliest published translation of chapter, nil when
+// there is none in the provider language
+func (p *MangaDexComicInfoProvider) chapter(
+	ctx context.Context,
+	mangaID, chapter string,
+) (*Chapter, error) {
+	var list ChapterList
+	err := p.get(ctx, "/chapter", url.Values{
+		"manga":                {mangaID},
+		"chapter":              {provider.TrimChapter(chapter)},
+		"translatedLanguage[]": {p.language},
+		"includes[]":           {"scanlation_group"},
+		"order[publishAt]":     {"asc"},
+		"limit":                {"10"},
+	}, &list)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Data) == 0 {
+		return nil, nil
+	}
+
+	return &list.Data[0], nil
+}
+
+func (p *MangaDexComicInfoProvider) provideChapter(
+	ctx context.Context,
+	manga *Manga,
+	chapter string,
+) (*standard.ComicInfoChapter, error) {
+	ci := parseChapter(manga)
+
+	c, err := p.chapter(ctx, manga.ID, chapter)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return ci, nil
+	}
+
+	a := c.Attributes
+	ci.Title = a.Title
+	ci.Number = a.Chapter
+	ci.Volume, _ = strconv.Atoi(a.Volume)
+	ci.PageCount = a.Pages
+	ci.LanguageISO = a.TranslatedLanguage
+	if t, err := time.Parse(time.RFC3339, a.PublishAt); err == nil {
+		ci.Year, ci.Month, ci.Day = t.Year(), int(t.Month()), t.Day()
+	}
+
+	groups := []string{}
+	for _, r := range c.Relationships {
+		if r.Type == "scanlation_group" && r.Attributes.Name != "" {
+			groups = append(groups, r.Attributes.Name)
+		}
+	}
+	ci.ScanInformation = strings.Join(groups, ", ")
+
+	return ci, nil
+}
+
+// get calls a mangadex api path and decodes the json response into res
+func (p *MangaDexComicInfoProvider) get(
+	ctx context.Context,
+	path string,
+	params url.Values,
+	res any,
+) error {
+	data, err := p.client.Get(ctx, path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, res); err != nil {
+		return yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+
+	return nil
+}
+
+// validate fails responses whose result isn't ok, so errors sent with a 2xx
+// status aren't decoded or cached
+func validate(data []byte) error {
+	var status struct {
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+	if status.Result != "ok" {
+		return yerr.WithStackf("mangadex response: %s", status.Result)
+	}
+	return nil
+}
+
+// languages maps the original language to the reading direction
+var languages = map[string]string{
+	"ja":    "YesAndRightToLeft",
+	"ko":    "Yes",
+	"zh":    "Yes",
+	"zh-hk": "Yes",
+}
+
+// ageRatings maps mangadex content ratings to ComicInfo age ratings
+var ageRatings = map[string]string{
+	"safe":         "Everyone",
+	"suggestive":   "Teen",
+	"erotica":      "Mature 17+",
+	"pornographic": "Adults Only 18+",
+}
+
+func parseChapter(m *Manga) *standard.ComicInfoChapter {
+	genres, tags := tagGroups(m)
+	ci := &standard.ComicInfoChapter{
+		Summary:    description(m),
+		Notes:      "Autogenerated with yuzu 🍋",
+		Genre:      genres,
+		Tags:       tags,
+		Manga:      languages[m.Attributes.OriginalLanguage],
+		AgeRating:  ageRatings[m.Attributes.ContentRating],
+		Web:        "https://mangadex.org/title/" + m.ID,
+		ProviderID: m.ID,
+	}
+	ci.Count, _ = strconv.Atoi(m.Attributes.LastChapter)
+
+	for _, r := range m.Relationships {
+		switch r.Type {
+		case "author":
+			ci.Writer = ci.Writer.Add(r.Attributes.Name)
+		case "artist":
+			ci.Penciller = ci.Penciller.Add(r.Attributes.Name)
+		}
+	}
+
+	standard.GetTitlePreference().Apply(ci, titles(m))
+
+	return ci
+}
+
+// statuses maps mangadex's manga status to the standard series status
+var statuses = map[string]string{
+	"ongoing":   standard.SeriesStatusOngoing,
+	"completed": standard.SeriesStatusEnded,
+	"hiatus":    standard.SeriesStatusHiatus,
+	"cancelled": standard.SeriesStatusAbandoned,
+}
+
+func parseSeries(m *Manga) *standard.ComicInfoSeries {
+	genres, tags := tagGroups(m)
+	cs := &standard.ComicInfoSeries{
+		Summary:    description(m),
+		Status:     statuses[m.Attributes.Status],
+		Year:       m.Attributes.Year,
+		Genre:      genres,
+		Tags:       tags,
+		AgeRating:  ageRatings[m.Attributes.ContentRating],
+		Manga:      languages[m.Attributes.OriginalLanguage],
+		CoverURL:   cover(m),
+		Web:        "https://mangadex.org/title/" + m.ID,
+		ProviderID: m.ID,
+	}
+	cs.Count, _ = strconv.Atoi(m.Attributes.LastChapter)
+	cs.VolumeCount, _ = strconv.Atoi(m.Attributes.LastVolume)
+
+	standard.GetTitlePreference().ApplySeries(cs, titles(m))
+
+	return cs
+}
+
+// titles lists the main title first then the alternative ones, mangadex
+// marks romanizations with a "-ro" suffix
+func titles(m *Manga) []standard.Title {
+	titles := []standard.Title{}
+	add := func(ls LocalizedString) {
+		for _, lang := range slices.Sorted(maps.Keys(ls)) {
+			v := ls[lang]
+			if base, ok := strings.CutSuffix(lang, "-ro"); ok {
+				lang = base + "-Latn"
+			}
+			titles = append(titles, standard.Title{Lang: lang, Value: v})
+		}
+	}
+
+	add(m.Attributes.Title)
+	for _, alt := range m.Attributes.AltTitles {
+		add(alt)
+	}
+	return titles
+}
+
+// tagGroups splits the tags of m into genres and every other tag
+func tagGroups(m *Manga) (genres, tags standard.List) {
+	for _, t := range m.Attributes.Tags {
+		name := t.Attributes.Name["en"]
+		if t.Attributes.Group == "genre" {
+			genres = genres.Add(name)
+		} else {
+			tags = tags.Add(name)
+		}
+	}
+	return genres, tags
+}
+
+// format returns the manga type used to score search candidates, mangadex
+// only says it through tags
+func format(m *Manga) string {
+	for _, t := range m.Attributes.Tags {
+		if t.Attributes.Name["en"] == "Oneshot" {
+			return "oneshot"
+		}
+	}
+	return "manga"
+}
+
+func description(m *Manga) string {
+	d := m.Attributes.Description
+	return strings.TrimSpace(cmp.Or(d["en"], d[m.Attributes.OriginalLanguage]))
+}
+
+func cover(m *Manga) string {
+	for _, r := range m.Relationships {
+		if r.Type == "cover_art" && r.Attributes.FileName != "" {
+			return fmt.Sprintf(coverURL, m.ID, r.Attributes.FileName)
+		}
+	}
+	return ""
+}
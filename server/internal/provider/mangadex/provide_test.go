@@ -0,0 +1,65 @@
+package mangadex
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/pkg/req/reqtest"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+func TestProvideChapter(t *testing.T) {
+	p := NewMangaDexProvider(reqtest.Client(t, "testdata", DefaultBaseURL), "")
+
+	want := &standard.ComicInfoChapter{
+		Title:           "Yotsuba & Moving",
+		Series:          "Yotsuba&!",
+		Number:          "1",
+		Count:           118,
+		Volume:          1,
+		AlternateSeries: "Yotsubato!",
+		Summary:         "Yotsuba is a strange little girl with a big personality.",
+		Notes:           "Autogenerated with yuzu 🍋",
+		Year:            2018,
+		Month:           1,
+		Day:             20,
+		Writer:          standard.List{"Azuma Kiyohiko"},
+		Penciller:       standard.List{"Azuma Kiyohiko"},
+		Genre:           standard.List{"Comedy", "Slice of Life"},
+		Tags:            standard.List{"Kids"},
+		Web:             "https://mangadex.org/title/58be6aa6-06cb-4ca5-bd20-f1392ce451fb",
+		PageCount:       37,
+		LanguageISO:     "en",
+		Manga:           "YesAndRightToLeft",
+		ScanInformation: "Yen Press, Fan Group",
+		AgeRating:       "Everyone",
+		ProviderID:      "58be6aa6-06cb-4ca5-bd20-f1392ce451fb",
+	}
+	// file names pad the number, the api only knows "1"
+	for _, chapter := range []string{"1", "001"} {
+		got, err := p.ProvideChapter(context.Background(), "Yotsuba&!", chapter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ProvideChapter(%q) =\n%+v\nwant\n%+v", chapter, got, want)
+		}
+	}
+}
+
+func TestProvideSeries(t *testing.T) {
+	p := NewMangaDexProvider(reqtest.Client(t, "testdata", DefaultBaseURL), "")
+
+	got, err := p.ProvideSeries(context.Background(), "Yotsuba&!")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "https://uploads.mangadex.org/covers/" +
+		"58be6aa6-06cb-4ca5-bd20-f1392ce451fb/cover.jpg"
+	if got.Status != standard.SeriesStatusOngoing || got.VolumeCount != 15 ||
+		got.CoverURL != want {
+		t.Errorf("ProvideSeries() = %+v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package mangadex
+
+// LocalizedString maps language codes to text, romanizations use codes like
+// "ja-ro"
+type LocalizedString map[string]string
+
+// Relationship links an entity to another, attributes are only set for the
+// types asked for with includes[]
+type Relationship struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Name     string `json:"name"`
+		FileName string `json:"fileName"`
+	} `json:"attributes"`
+}
+
+type Manga struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Title                  LocalizedString   `json:"title"`
+		AltTitles              []LocalizedString `json:"altTitles"`
+		Description            LocalizedString   `json:"description"`
+		OriginalLanguage       string            `json:"originalLanguage"`
+		LastVolume             string            `json:"lastVolume"`
+		LastChapter            string            `json:"lastChapter"`
+		PublicationDemographic string            `json:"publicationDemographic"`
+		Status                 string            `json:"status"`
+		Year                   int               `json:"year"`
+		ContentRating          string            `json:"contentRating"`
+		Tags                   []struct {
+			Attributes struct {
+				Name LocalizedString `json:"name"`
+				// Group is one of "genre", "theme", "format" or "content"
+				Group string `json:"group"`
+			} `json:"attributes"`
+		} `json:"tags"`
+	} `json:"attributes"`
+	Relationships []Relationship `json:"relationships"`
+}
+
+type Chapter struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Volume             string `json:"volume"`
+		Chapter            string `json:"chapter"`
+		Title              string `json:"title"`
+		TranslatedLanguage string `json:"translatedLanguage"`
+		PublishAt          string `json:"publishAt"`
+		Pages              int    `json:"pages"`
+	} `json:"attributes"`
+	Relationships []Relationship `json:"relationships"`
+}
+
+type MangaList struct {
+	Result string  `json:"result"`
+	Data   []Manga `json:"data"`
+}
+
+type MangaEntity struct {
+	Result string `json:"result"`
+	Data   Manga  `json:"data"`
+}
+
+type ChapterList struct {
+	Result string    `json:"result"`
+	Data   []Chapter `json:"data"`
+}
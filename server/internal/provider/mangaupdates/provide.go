@@ -0,0 +1,329 @@
+// Package mangaupdates implements the provider interface for mangaupdates
+// (baka-updates) metadata
+package mangaupdates
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vyxn/yuzu/internal/pkg/req"
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// DefaultBaseURL is the api used by the registered provider
+const DefaultBaseURL = "https://api.mangaupdates.com/v1"
+
+const (
+	searchPath = "/series/search"
+	seriesPath = "/series/%s"
+)
+
+// maxTags bounds the categories kept as tags, mangaupdates series often have
+// hundreds of them
+const maxTags = 25
+
+func init() {
+	provider.Register(provider.Registration{
+		Name: "mangaupdates",
+		Capabilities: []provider.Capability{
+			provider.CapabilityChapter,
+			provider.CapabilitySearch,
+			provider.CapabilityByID,
+			provider.CapabilitySeries,
+		},
+		Priority: 35,
+		New: func(map[string]string) (provider.ComicInfoProvider, error) {
+			return NewMangaUpdatesProvider(nil), nil
+		},
+	})
+}
+
+type MangaUpdatesComicInfoProvider struct {
+	client *req.Client
+}
+
+// NewMangaUpdatesProvider calls the api with client, nil uses DefaultBaseURL
+func NewMangaUpdatesProvider(client *req.Client) *MangaUpdatesComicInfoProvider {
+	if client == nil {
+		client = req.NewClient(DefaultBaseURL, nil)
+	}
+	return &MangaUpdatesComicInfoProvider{client}
+}
+
+func (p *MangaUpdatesComicInfoProvider) Name() string { return "mangaupdates" }
+
+func (p *MangaUpdatesComicInfoProvider) ProvideChapter(
+	ctx context.Context, series, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	s, err := p.findSeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get mangaupdates comicinfo: %w", err)
+	}
+
+	return parseChapter(s), nil
+}
+
+func (p *MangaUpdatesComicInfoProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	if err := provider.CheckNumericID(id); err != nil {
+		return nil, err
+	}
+
+	s, err := p.series(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get mangaupdates comicinfo: %w", err)
+	}
+
+	return parseChapter(s), nil
+}
+
+func (p *MangaUpdatesComicInfoProvider) ProvideSeries(
+	ctx context.Context, series string,
+) (*standard.ComicInfoSeries, error) {
+	s, err := p.findSeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get mangaupdates series info: %w", err)
+	}
+
+	return parseSeries(s), nil
+}
+
+func (p *MangaUpdatesComicInfoProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	body, err := json.Marshal(map[string]any{
+		"search":  series,
+		"perpage": 10,
+	})
+	if err != nil {
+		return nil, yerr.WithStackf("encoding search: %w", err)
+	}
+
+	data, err := p.client.Post(
+		ctx,
+		searchPath,
+		map[string]string{"Content-Type": "application/json"},
+		body,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var res SearchResponse
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+
+	candidates := make([]provider.SeriesCandidate, 0, len(res.Results))
+	for _, r := range res.Results {
+		c := provider.SeriesCandidate{
+			ProviderID: strconv.FormatInt(r.Record.SeriesID, 10),
+			Titles:     []standard.Title{{Value: r.Record.Title}},
+			Type:       r.Record.Type,
+			CoverURL:   r.Record.Image.URL.Original,
+		}
+		// hit_title is the associated name the search matched on
+		if r.HitTitle != "" && r.HitTitle != r.Record.Title {
+			c.Titles = append(c.Titles, standard.Title{Value: r.HitTitle})
+		}
+		c.Year, _ = strconv.Atoi(r.Record.Year)
+		candidates = append(candidates, c)
+	}
+
+	return provider.Rank(series, candidates), nil
+}
+
+// findSeries fetches the details of the best search match for series
+func (p *MangaUpdatesComicInfoProvider) findSeries(
+	ctx context.Context,
+	series string,
+) (*Series, error) {
+	candidates, err := p.SearchSeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("finding series: %w", err)
+	}
+	best, err := provider.Best(series, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("finding series: %w", err)
+	}
+
+	return p.series(ctx, best.ProviderID)
+}
+
+func (p *MangaUpdatesComicInfoProvider) series(
+	ctx context.Context,
+	id string,
+) (*Series, error) {
+	data, err := p.client.Get(ctx, fmt.Sprintf(seriesPath, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res Series
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+
+	return &res, nil
+}
+
+// types maps mangaupdates series types to the reading direction
+var types = map[string]string{
+	"Manga":     "YesAndRightToLeft",
+	"Doujinshi": "YesAndRightToLeft",
+	"Manhwa":    "Yes",
+	"Manhua":    "Yes",
+	"OEL":       "No",
+}
+
+func parseChapter(s *Series) *standard.ComicInfoChapter {
+	ci := &standard.ComicInfoChapter{
+		Summary:         provider.StripHTML(s.Description),
+		Notes:           "Autogenerated with yuzu 🍋",
+		Count:           count(s),
+		Genre:           genres(s),
+		Tags:            tags(s),
+		Manga:           types[s.Type],
+		CommunityRating: rating(s.BayesianRating),
+		Web:             s.URL,
+		ProviderID:      strconv.FormatInt(s.SeriesID, 10),
+	}
+	ci.Year, _ = strconv.Atoi(s.Year)
+	ci.Publisher = publisher(s)
+
+	for _, a := range s.Authors {
+		switch a.Type {
+		case "Author":
+			ci.Writer = ci.Writer.Add(a.Name)
+		case "Artist":
+			ci.Penciller = ci.Penciller.Add(a.Name)
+		}
+	}
+
+	standard.GetTitlePreference().Apply(ci, titles(s))
+
+	return ci
+}
+
+var reVolumes = regexp.MustCompile(`(\d+)\s+Volumes?`)
+
+func parseSeries(s *Series) *standard.ComicInfoSeries {
+	cs := &standard.ComicInfoSeries{
+		Summary:         provider.StripHTML(s.Description),
+		Status:          status(s),
+		Count:           count(s),
+		Genre:           genres(s),
+		Tags:            tags(s),
+		Manga:           types[s.Type],
+		CommunityRating: rating(s.BayesianRating),
+		CoverURL:        s.Image.URL.Original,
+		Web:             s.URL,
+		ProviderID:      strconv.FormatInt(s.SeriesID, 10),
+	}
+	cs.Year, _ = strconv.Atoi(s.Year)
+	cs.Publisher = publisher(s)
+	if m := reVolumes.FindStringSubmatch(s.Status); m != nil {
+		cs.VolumeCount, _ = strconv.Atoi(m[1])
+	}
+
+	standard.GetTitlePreference().ApplySeries(cs, titles(s))
+
+	return cs
+}
+
+// status reads the free text status, e.g. "14 Volumes (Ongoing)"
+func status(s *Series) string {
+	text := strings.ToLower(s.Status)
+	switch {
+	case s.Completed || strings.Contains(text, "complete"):
+		return standard.SeriesStatusEnded
+	case strings.Contains(text, "hiatus"):
+		return standard.SeriesStatusHiatus
+	case strings.Contains(text, "cancel"),
+		strings.Contains(text, "discontinued"),
+		strings.Contains(text, "dropped"):
+		return standard.SeriesStatusAbandoned
+	case strings.Contains(text, "ongoing"):
+		return standard.SeriesStatusOngoing
+	}
+	return standard.SeriesStatusUnknown
+}
+
+// count is the latest chapter once the series is completed, before that it
+// isn't the total
+func count(s *Series) int {
+	if status(s) != standard.SeriesStatusEnded {
+		return 0
+	}
+	return s.LatestChapter
+}
+
+// publisher returns the original publisher, or the english licensor when
+// english titles are preferred, either stands in when the other is missing
+func publisher(s *Series) string {
+	original, english := "", ""
+	for _, p := range s.Publishers {
+		switch p.Type {
+		case "Original":
+			original = cmp.Or(original, p.PublisherName)
+		case "English":
+			english = cmp.Or(english, p.PublisherName)
+		}
+	}
+	if standard.GetTitlePreference().Prefers("en") {
+		return cmp.Or(english, original)
+	}
+	return cmp.Or(original, english)
+}
+
+func genres(s *Series) standard.List {
+	out := standard.List{}
+	for _, g := range s.Genres {
+		out = out.Add(g.Genre)
+	}
+	return out
+}
+
+// tags keeps the categories voted up the most
+func tags(s *Series) standard.List {
+	categories := slices.Clone(s.Categories)
+	slices.SortStableFunc(categories, func(a, b Category) int {
+		return b.Votes - a.Votes
+	})
+
+	out := standard.List{}
+	for _, c := range categories {
+		if len(out) == maxTags {
+			break
+		}
+		if c.VotesPlus > c.VotesMinus {
+			out = out.Add(c.Category)
+		}
+	}
+	return out
+}
+
+// rating converts the 0-10 bayesian rating to the ComicInfo 0-5 range
+func rating(r float64) float64 {
+	return math.Round(r*5) / 10
+}
+
+// titles lists the main title and every associated name, mangaupdates
+// doesn't say which language they are in
+func titles(s *Series) []standard.Title {
+	titles := []standard.Title{{Value: s.Title}}
+	for _, a := range s.Associated {
+		titles = append(titles, standard.Title{Value: a.Title})
+	}
+	return titles
+}
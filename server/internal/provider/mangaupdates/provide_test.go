@@ -0,0 +1,110 @@
+package mangaupdates
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/pkg/req/reqtest"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+func TestProvideChapter(t *testing.T) {
+	p := NewMangaUpdatesProvider(reqtest.Client(t, "testdata", DefaultBaseURL))
+
+	got, err := p.ProvideChapter(context.Background(), "Yotsuba&!", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &standard.ComicInfoChapter{
+		Series:          "Yotsuba to!",
+		AlternateSeries: "Yotsuba&!",
+		Summary:         "Yotsuba is a strange little girl.\n\nShe moves to a new town.",
+		Notes:           "Autogenerated with yuzu 🍋",
+		Year:            2003,
+		Writer:          standard.List{"AZUMA Kiyohiko"},
+		Penciller:       standard.List{"AZUMA Kiyohiko"},
+		Publisher:       "Yen Press",
+		Genre:           standard.List{"Comedy", "Slice of Life"},
+		Tags:            standard.List{"Father-Daughter Relationship", "Child Protagonist"},
+		Web:             "https://www.mangaupdates.com/series/wq8kxpn/yotsuba-to",
+		Manga:           "YesAndRightToLeft",
+		CommunityRating: 4.5,
+		ProviderID:      "57324441471",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProvideChapter() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestProvideSeries(t *testing.T) {
+	p := NewMangaUpdatesProvider(reqtest.Client(t, "testdata", DefaultBaseURL))
+
+	got, err := p.ProvideSeries(context.Background(), "Yotsuba&!")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Status != standard.SeriesStatusOngoing || got.VolumeCount != 15 ||
+		got.Count != 0 || got.Publisher != "Yen Press" || got.Imprint != "" {
+		t.Errorf("ProvideSeries() = %+v", got)
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	t.Cleanup(func() {
+		standard.SetTitlePreference(standard.DefaultTitlePreference)
+	})
+
+	tests := []struct {
+		name       string
+		preference string
+		publishers string
+		want       string
+	}{
+		{
+			"english preferred",
+			"en,ja",
+			`[{"publisher_name":"Kodansha","type":"Original"},
+			{"publisher_name":"Dark Horse","type":"English"}]`,
+			"Dark Horse",
+		},
+		{
+			"japanese preferred",
+			"ja,en",
+			`[{"publisher_name":"Kodansha","type":"Original"},
+			{"publisher_name":"Dark Horse","type":"English"}]`,
+			"Kodansha",
+		},
+		{
+			"no english licensor",
+			"en",
+			`[{"publisher_name":"Kodansha","type":"Original"}]`,
+			"Kodansha",
+		},
+		{
+			"no original publisher",
+			"ja",
+			`[{"publisher_name":"Dark Horse","type":"English"}]`,
+			"Dark Horse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Series
+			err := json.Unmarshal([]byte(`{"publishers":`+tt.publishers+`}`), &s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			standard.SetTitlePreference(
+				standard.ParseTitlePreference(tt.preference),
+			)
+
+			if got := publisher(&s); got != tt.want {
+				t.Errorf("publisher() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package mangaupdates
+
+// Record is a series as returned by the search endpoint
+type Record struct {
+	SeriesID int64  `json:"series_id"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Type     string `json:"type"`
+	Year     string `json:"year"`
+	Image    struct {
+		URL struct {
+			Original string `json:"original"`
+		} `json:"url"`
+	} `json:"image"`
+}
+
+// SearchResponse is the response of the series search endpoint
+type SearchResponse struct {
+	TotalHits int `json:"total_hits"`
+	Results   []struct {
+		Record   Record `json:"record"`
+		HitTitle string `json:"hit_title"`
+	} `json:"results"`
+}
+
+// Series is the response of the series detail endpoint
+type Series struct {
+	Record
+	Associated []struct {
+		Title string `json:"title"`
+	} `json:"associated"`
+	Description    string  `json:"description"`
+	BayesianRating float64 `json:"bayesian_rating"`
+	Genres         []struct {
+		Genre string `json:"genre"`
+	} `json:"genres"`
+	Categories    []Category `json:"categories"`
+	LatestChapter int        `json:"latest_chapter"`
+	Status        string     `json:"status"`
+	Completed     bool       `json:"completed"`
+	Authors       []struct {
+		Name string `json:"name"`
+		// Type is "Author" or "Artist"
+		Type string `json:"type"`
+	} `json:"authors"`
+	Publishers []struct {
+		PublisherName string `json:"publisher_name"`
+		// Type is "Original" or "English"
+		Type string `json:"type"`
+	} `json:"publishers"`
+}
+
+// Category is a user voted tag of a series
+type Category struct {
+	Category   string `json:"category"`
+	Votes      int    `json:"votes"`
+	VotesPlus  int    `json:"votes_plus"`
+	VotesMinus int    `json:"votes_minus"`
+}
@@ -0,0 +1,220 @@
+// Package match scores provider search results against a series folder name
+package match
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// Media types candidates are grouped in
+const (
+	TypeUnknown = ""
+	TypeManga   = "manga"
+	TypeOneShot = "oneshot"
+	TypeNovel   = "novel"
+)
+
+// types maps the media types of every provider to the groups above
+var types = map[string]string{
+	"manga":       TypeManga,
+	"manhwa":      TypeManga,
+	"manhua":      TypeManga,
+	"oel":         TypeManga,
+	"doujin":      TypeManga,
+	"doujinshi":   TypeManga,
+	"comic":       TypeManga,
+	"oneshot":     TypeOneShot,
+	"one_shot":    TypeOneShot,
+	"one-shot":    TypeOneShot,
+	"novel":       TypeNovel,
+	"light_novel": TypeNovel,
+	"lightnovel":  TypeNovel,
+	"light novel": TypeNovel,
+	"ln":          TypeNovel,
+}
+
+// NormalizeType maps a provider media type to one of the Type constants
+func NormalizeType(t string) string {
+	return types[strings.ToLower(strings.TrimSpace(t))]
+}
+
+// Query is what is known about a series from its folder name
+type Query struct {
+	Title string
+	Year  int
+	Type  string
+}
+
+var (
+	reYear = regexp.MustCompile(`\s*[(\[](\d{4})[)\]]\s*$`)
+	reType = regexp.MustCompile(
+		`(?i)\s*[(\[](light novel|novel|ln|one[-_ ]?shot|manga|manhwa|manhua)[)\]]\s*$`,
+	)
+)
+
+// ParseQuery extracts a trailing "(2016)" year and "[Novel]" style media type
+// from a folder name
+func ParseQuery(folder string) Query {
+	q := Query{Title: strings.TrimSpace(folder)}
+	for {
+		if m := reYear.FindStringSubmatch(q.Title); m != nil && q.Year == 0 {
+			q.Year, _ = strconv.Atoi(m[1])
+			q.Title = strings.TrimSpace(q.Title[:len(q.Title)-len(m[0])])
+			continue
+		}
+		if m := reType.FindStringSubmatch(q.Title); m != nil && q.Type == "" {
+			q.Type = NormalizeType(m[1])
+			q.Title = strings.TrimSpace(q.Title[:len(q.Title)-len(m[0])])
+			continue
+		}
+		return q
+	}
+}
+
+// Candidate is a search result to score
+type Candidate struct {
+	Titles []string
+	Year   int
+	Type   string
+}
+
+// Score rates how well c matches q between 0 and 1
+func Score(q Query, c Candidate) float64 {
+	title := Normalize(q.Title)
+
+	best := 0.0
+	for _, t := range c.Titles {
+		best = max(best, Similarity(title, Normalize(t)))
+	}
+
+	if q.Year != 0 && c.Year != 0 {
+		switch d := abs(q.Year - c.Year); {
+		case d == 0:
+			best += 0.1
+		case d > 1:
+			best -= 0.2
+		}
+	}
+
+	typ := NormalizeType(c.Type)
+	switch {
+	case q.Type != TypeUnknown && typ != TypeUnknown && q.Type != typ:
+		best -= 0.2
+	case q.Type == TypeUnknown && typ == TypeNovel:
+		// libraries hold comics, a novel with the same name is a worse match
+		best -= 0.15
+	}
+
+	return min(max(best, 0), 1)
+}
+
+var (
+	articles = []string{"the ", "a ", "an "}
+	// romanization variants of long vowels
+	romanization = strings.NewReplacer(
+		"ou", "o",
+		"oo", "o",
+		"oh", "o",
+		"uu", "u",
+		"aa", "a",
+		"ii", "i",
+	)
+	// particles are replaced as whole words only
+	particles = map[string]string{
+		"wo": "o",
+		"wa": "ha",
+	}
+	diacritics = transform.Chain(
+		norm.NFD,
+		runes.Remove(runes.In(unicode.Mn)),
+		norm.NFC,
+	)
+)
+
+// Normalize folds width, case, diacritics, punctuation, leading articles and
+// romanization variants so equivalent titles compare equal
+func Normalize(s string) string {
+	s = width.Fold.String(s)
+	s = strings.ToLower(s)
+	if d, _, err := transform.String(diacritics, s); err == nil {
+		s = d
+	}
+	s = reYear.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "&", " and ")
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, s)
+	s = strings.Join(strings.Fields(s), " ")
+
+	for _, a := range articles {
+		if rest, ok := strings.CutPrefix(s, a); ok && rest != "" {
+			s = rest
+			break
+		}
+	}
+
+	words := strings.Fields(s)
+	for i, w := range words {
+		if p, ok := particles[w]; ok {
+			w = p
+		}
+		words[i] = romanization.Replace(w)
+	}
+	return strings.Join(words, " ")
+}
+
+// Similarity is the Sørensen–Dice coefficient of the letter pairs of a and b,
+// both should already be normalized
+func Similarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	pa, pb := bigrams(a), bigrams(b)
+	if len(pa) == 0 || len(pb) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, p := range pa {
+		counts[p]++
+	}
+	common := 0
+	for _, p := range pb {
+		if counts[p] > 0 {
+			counts[p]--
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(pa)+len(pb))
+}
+
+func bigrams(s string) []string {
+	r := []rune(strings.ReplaceAll(s, " ", ""))
+	out := make([]string, 0, len(r))
+	for i := 0; i+1 < len(r); i++ {
+		out = append(out, string(r[i:i+2]))
+	}
+	return out
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
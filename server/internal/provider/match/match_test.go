@@ -0,0 +1,201 @@
+package match
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Shingeki no Kyojin", "shingeki no kyojin"},
+		{"Ｏｎｅ Ｐｉｅｃｅ", "one piece"},
+		{"Pokémon Adventures", "pokemon adventures"},
+		{"Kaguya-sama: Love is War", "kaguya sama love is war"},
+		{"Spy×Family", "spy family"},
+		{"Fullmetal Alchemist & Co", "fullmetal alchemist and co"},
+		{"Berserk (1989)", "berserk"},
+		{"The Promised Neverland", "promised neverland"},
+		{"A Silent Voice", "silent voice"},
+		{"An", "an"},
+		{"The", "the"},
+		// long vowels
+		{"Toukyou Ghoul", "tokyo ghol"},
+		{"Yuuki Yuuna", "yuki yuna"},
+		{"Shiina Mashiro", "shina mashiro"},
+		{"Oh! Great", "o great"},
+		// particles only at word boundaries
+		{"Ore wo Suki nano wa Omae dake ka yo", "ore o suki nano ha omae dake ka yo"},
+		{"Kimi wa", "kimi ha"},
+		{"Wa Ore", "ha ore"},
+		{"Wotakoi", "wotakoi"},
+		{"Kowai Hanashi", "kowai hanashi"},
+		{"Sawako wo wo", "sawako o o"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"berserk", "berserk", 1},
+		{"", "berserk", 0},
+		{"berserk", "", 0},
+		{"a", "b", 0},
+		{"a", "a", 1},
+		{"night", "nacht", 0.25},
+		{"ab cd", "abcd", 1},
+		{"abab", "ab", 0.5},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got, rev := Similarity(tt.a, tt.b), Similarity(tt.b, tt.a); got != rev {
+			t.Errorf("Similarity(%q, %q) = %v, reversed %v", tt.a, tt.b, got, rev)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Query
+	}{
+		{"Berserk", Query{Title: "Berserk"}},
+		{"  Berserk  ", Query{Title: "Berserk"}},
+		{"Berserk (1989)", Query{Title: "Berserk", Year: 1989}},
+		{"Berserk [1989]", Query{Title: "Berserk", Year: 1989}},
+		{"Overlord [Light Novel]", Query{Title: "Overlord", Type: TypeNovel}},
+		{"Overlord (2012) [LN]", Query{Title: "Overlord", Year: 2012, Type: TypeNovel}},
+		{"Overlord [Manga] (2012)", Query{Title: "Overlord", Year: 2012, Type: TypeManga}},
+		{"Solo Leveling (Manhwa)", Query{Title: "Solo Leveling", Type: TypeManga}},
+		{"Look Back [One-Shot]", Query{Title: "Look Back", Type: TypeOneShot}},
+		{"1984", Query{Title: "1984"}},
+		{"Title (12345)", Query{Title: "Title (12345)"}},
+		{"Title (2016) Extra", Query{Title: "Title (2016) Extra"}},
+		// only the last year counts
+		{"Title (2016) (2017)", Query{Title: "Title (2016)", Year: 2017}},
+	}
+	for _, tt := range tests {
+		if got := ParseQuery(tt.in); got != tt.want {
+			t.Errorf("ParseQuery(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name string
+		q    Query
+		c    Candidate
+		want float64
+	}{
+		{
+			"exact title",
+			Query{Title: "Berserk"},
+			Candidate{Titles: []string{"Berserk"}},
+			1,
+		},
+		{
+			"best of the titles",
+			Query{Title: "Shingeki no Kyojin"},
+			Candidate{Titles: []string{"Attack on Titan", "Shingeki no Kyojin"}},
+			1,
+		},
+		{
+			"no titles",
+			Query{Title: "Berserk"},
+			Candidate{},
+			0,
+		},
+		{
+			"same year bonus is capped",
+			Query{Title: "Berserk", Year: 1989},
+			Candidate{Titles: []string{"Berserk"}, Year: 1989},
+			1,
+		},
+		{
+			"same year bonus",
+			Query{Title: "night", Year: 2000},
+			Candidate{Titles: []string{"nacht"}, Year: 2000},
+			0.35,
+		},
+		{
+			"year off by one",
+			Query{Title: "Berserk", Year: 1989},
+			Candidate{Titles: []string{"Berserk"}, Year: 1990},
+			1,
+		},
+		{
+			"year off by two",
+			Query{Title: "Berserk", Year: 1989},
+			Candidate{Titles: []string{"Berserk"}, Year: 1991},
+			0.8,
+		},
+		{
+			"unknown years",
+			Query{Title: "Berserk"},
+			Candidate{Titles: []string{"Berserk"}, Year: 1991},
+			1,
+		},
+		{
+			"type mismatch",
+			Query{Title: "Overlord", Type: TypeManga},
+			Candidate{Titles: []string{"Overlord"}, Type: "light_novel"},
+			0.8,
+		},
+		{
+			"type match",
+			Query{Title: "Overlord", Type: TypeNovel},
+			Candidate{Titles: []string{"Overlord"}, Type: "Light Novel"},
+			1,
+		},
+		{
+			"novels rank below comics",
+			Query{Title: "Overlord"},
+			Candidate{Titles: []string{"Overlord"}, Type: "novel"},
+			0.85,
+		},
+		{
+			"penalties stop at zero",
+			Query{Title: "night", Year: 2000, Type: TypeManga},
+			Candidate{Titles: []string{"nacht"}, Year: 2010, Type: "novel"},
+			0,
+		},
+		{
+			// exactly the 0.5 MinScore providers accept
+			"minimum match",
+			Query{Title: "abab"},
+			Candidate{Titles: []string{"ab"}},
+			0.5,
+		},
+		{
+			"minimum match lowered by the year",
+			Query{Title: "abab", Year: 2000},
+			Candidate{Titles: []string{"ab"}, Year: 2002},
+			0.3,
+		},
+		{
+			"minimum match raised by the year",
+			Query{Title: "abab", Year: 2000},
+			Candidate{Titles: []string{"ab"}, Year: 2000},
+			0.6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.q, tt.c); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
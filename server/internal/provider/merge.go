@@ -2,29 +2,65 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"time"
 
 	"github.com/vyxn/yuzu/internal/standard"
 )
 
+// MergeOptions tunes MergedComicInfoChapter
+type MergeOptions struct {
+	Policy MergePolicy
+	// Timeout bounds every provider call, DefaultTimeout when zero
+	Timeout time.Duration
+	// Required names the providers that must succeed, when empty any one
+	// provider succeeding is enough
+	Required []string
+	// Override is applied on top of the merged result, it is enough on its
+	// own when no provider succeeds
+	Override *Override
+}
+
+// MergedComicInfoChapter asks every provider for the chapter in parallel and
+// merges the results that succeeded field by field following opts.Policy, the
+// providers that failed are returned alongside
 func MergedComicInfoChapter(
 	ctx context.Context,
+	opts MergeOptions,
 	series, chapter string,
 	providers ...ComicInfoProvider,
-) (*standard.ComicInfoChapter, error) {
-	out := &standard.ComicInfoChapter{}
-
-	for _, p := range providers {
-		if ci, err := p.ProvideChapter(ctx, series, chapter); err == nil {
-			MergeStructs(out, ci)
-		} else {
-			return nil, err
-		}
+) (*standard.ComicInfoChapter, Provenance, ProviderErrors, error) {
+	results, failed := FanOut(
+		ctx,
+		opts.Timeout,
+		providers,
+		func(
+			ctx context.Context,
+			p ComicInfoProvider,
+		) (*standard.ComicInfoChapter, error) {
+			return p.ProvideChapter(ctx, series, chapter)
+		},
+	)
+	succeeded := len(results)
+	if opts.Override != nil {
+		succeeded++
+	}
+	if err := failed.Check(opts.Required, succeeded); err != nil {
+		return nil, nil, failed, err
 	}
 
-	return out, nil
+	ci, provenance := opts.Policy.Merge(results)
+	if opts.Override != nil {
+		opts.Override.Apply(ci, provenance)
+	}
+	return ci, provenance, failed, nil
 }
 
+var listType = reflect.TypeFor[standard.List]()
+
+// MergeStructs fills the zero fields of dst with the values of src,
+// multi-valued standard.List fields get the union of both instead
 func MergeStructs(dst, src any) {
 	dv := reflect.ValueOf(dst).Elem()
 	sv := reflect.ValueOf(src).Elem()
@@ -33,6 +69,16 @@ func MergeStructs(dst, src any) {
 		df := dv.Field(i)
 		sf := sv.Field(i)
 
+		if df.Type() == listType && df.CanSet() {
+			union := df.Interface().(standard.List).Union(
+				sf.Interface().(standard.List),
+			)
+			if len(union) > 0 {
+				df.Set(reflect.ValueOf(union))
+			}
+			continue
+		}
+
 		// skip if destination already has a non-zero value
 		if !df.IsZero() {
 			continue
@@ -44,3 +90,48 @@ func MergeStructs(dst, src any) {
 		}
 	}
 }
+
+// FillZero fills the zero fields of dst with the values of src, unlike
+// MergeStructs lists that are set in dst are kept as they are
+func FillZero(dst, src any) {
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src).Elem()
+
+	for i := 0; i < dv.NumField(); i++ {
+		if df := dv.Field(i); df.IsZero() && df.CanSet() {
+			df.Set(sv.Field(i))
+		}
+	}
+}
+
+// MergedComicInfoSeries merges the series metadata of every provider that
+// implements ComicInfoSeriesProvider, earlier providers take precedence,
+// providers that fail are skipped unless none of them succeed
+func MergedComicInfoSeries(
+	ctx context.Context,
+	series string,
+	providers ...ComicInfoProvider,
+) (*standard.ComicInfoSeries, error) {
+	out := &standard.ComicInfoSeries{}
+	var errs []error
+	merged := false
+
+	for _, p := range providers {
+		sp, ok := p.(ComicInfoSeriesProvider)
+		if !ok {
+			continue
+		}
+		cs, err := sp.ProvideSeries(ctx, series)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		MergeStructs(out, cs)
+		merged = true
+	}
+
+	if !merged && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return out, nil
+}
@@ -0,0 +1,348 @@
+// Package metron implements the provider interface for metron.cloud metadata
+package metron
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vyxn/yuzu/internal/pkg/req"
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// DefaultBaseURL is used when METRON_URL isn't set
+const DefaultBaseURL = "https://metron.cloud/api"
+
+func init() {
+	provider.Register(provider.Registration{
+		Name:        "metron",
+		Credentials: []string{"METRON_USERNAME", "METRON_PASSWORD"},
+		Capabilities: []provider.Capability{
+			provider.CapabilityChapter,
+			provider.CapabilitySearch,
+			provider.CapabilityByID,
+			provider.CapabilitySeries,
+		},
+		Priority: 12,
+		New: func(
+			credentials map[string]string,
+		) (provider.ComicInfoProvider, error) {
+			client := req.NewClient(
+				cmp.Or(os.Getenv("METRON_URL"), DefaultBaseURL),
+				nil,
+			)
+			return NewMetronProvider(
+				client,
+				credentials["METRON_USERNAME"],
+				credentials["METRON_PASSWORD"],
+			), nil
+		},
+	})
+}
+
+type MetronComicInfoProvider struct {
+	client        *req.Client
+	authorization string
+}
+
+// NewMetronProvider talks to the metron api with client, nil uses
+// DefaultBaseURL, so a local stand-in can be used instead
+func NewMetronProvider(
+	client *req.Client,
+	username, password string,
+) *MetronComicInfoProvider {
+	if client == nil {
+		client = req.NewClient(DefaultBaseURL, nil)
+	}
+	return &MetronComicInfoProvider{
+		client: client,
+		authorization: "Basic " + base64.StdEncoding.EncodeToString(
+			[]byte(username+":"+password),
+		),
+	}
+}
+
+func (p *MetronComicInfoProvider) Name() string { return "metron" }
+
+func (p *MetronComicInfoProvider) ProvideChapter(
+	ctx context.Context, series, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	id, err := p.findSeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get metron comicinfo: %w", err)
+	}
+
+	return p.provideChapter(ctx, id, chapter)
+}
+
+func (p *MetronComicInfoProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	if err := provider.CheckNumericID(id); err != nil {
+		return nil, err
+	}
+
+	return p.provideChapter(ctx, id, chapter)
+}
+
+func (p *MetronComicInfoProvider) ProvideSeries(
+	ctx context.Context, series string,
+) (*standard.ComicInfoSeries, error) {
+	id, err := p.findSeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get metron series info: %w", err)
+	}
+
+	var s Series
+	if err := p.get(ctx, "/series/"+id+"/", nil, &s); err != nil {
+		return nil, err
+	}
+
+	return parseSeries(&s), nil
+}
+
+func (p *MetronComicInfoProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	var list Page[SeriesListItem]
+	err := p.get(ctx, "/series/", url.Values{"name": {series}}, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]provider.SeriesCandidate, 0, len(list.Results))
+	for _, s := range list.Results {
+		candidates = append(candidates, provider.SeriesCandidate{
+			ProviderID: strconv.Itoa(s.ID),
+			Titles:     titles(s.Series),
+			Year:       s.YearBegan,
+			Type:       "comic",
+		})
+	}
+
+	return provider.Rank(series, candidates), nil
+}
+
+// findSeries returns the id of the best search match for series
+func (p *MetronComicInfoProvider) findSeries(
+	ctx context.Context,
+	series string,
+) (string, error) {
+	candidates, err := p.SearchSeries(ctx, series)
+	if err != nil {
+		return "", fmt.Errorf("finding series: %w", err)
+	}
+	best, err := provider.Best(series, candidates)
+	if err != nil {
+		return "", fmt.Errorf("finding series: %w", err)
+	}
+
+	return best.ProviderID, nil
+}
+
+// provideChapter fills the issue numbered chapter of the series with id, only
+// the series is used when metron doesn't have that issue
+func (p *MetronComicInfoProvider) provideChapter(
+	ctx context.Context,
+	id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	var series Series
+	if err := p.get(ctx, "/series/"+id+"/", nil, &series); err != nil {
+		return nil, err
+	}
+
+	var issues Page[IssueListItem]
+	err := p.get(ctx, "/issue/", url.Values{
+		"series_id": {id},
+		"number":    {provider.TrimChapter(chapter)},
+	}, &issues)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues.Results) == 0 {
+		return parseSeriesChapter(&series), nil
+	}
+
+	var issue Issue
+	path := fmt.Sprintf("/issue/%d/", issues.Results[0].ID)
+	if err := p.get(ctx, path, nil, &issue); err != nil {
+		return nil, err
+	}
+
+	return parseChapter(&series, &issue), nil
+}
+
+// get calls a metron api path and decodes the json response into res
+func (p *MetronComicInfoProvider) get(
+	ctx context.Context,
+	path string,
+	params url.Values,
+	res any,
+) error {
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	data, err := p.client.Get(ctx, path, map[string]string{
+		"Authorization": p.authorization,
+		"Accept":        "application/json",
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, res); err != nil {
+		return yerr.WithStackf("unmarshaling json response: %w", err)
+	}
+
+	return nil
+}
+
+// roles maps metron credit roles to the ComicInfo credit fields they fill
+var roles = map[string][]string{
+	"writer":            {"Writer"},
+	"script":            {"Writer"},
+	"story":             {"Writer"},
+	"plot":              {"Writer"},
+	"artist":            {"Penciller", "Inker"},
+	"illustrator":       {"Penciller", "Inker"},
+	"penciller":         {"Penciller"},
+	"breakdowns":        {"Penciller"},
+	"inker":             {"Inker"},
+	"finishes":          {"Inker"},
+	"embellisher":       {"Inker"},
+	"colorist":          {"Colorist"},
+	"color separations": {"Colorist"},
+	"color assists":     {"Colorist"},
+	"letterer":          {"Letterer"},
+	"cover":             {"CoverArtist"},
+	"editor":            {"Editor"},
+	"senior editor":     {"Editor"},
+	"group editor":      {"Editor"},
+	"associate editor":  {"Editor"},
+	"assistant editor":  {"Editor"},
+	"editor in chief":   {"Editor"},
+	"translator":        {"Translator"},
+}
+
+// ageRatings maps metron ratings to ComicInfo age ratings
+var ageRatings = map[string]string{
+	"Everyone":  "Everyone",
+	"Teen":      "Teen",
+	"Teen Plus": "Teen",
+	"Mature":    "Mature 17+",
+	"Explicit":  "Adults Only 18+",
+}
+
+func parseSeriesChapter(s *Series) *standard.ComicInfoChapter {
+	ci := &standard.ComicInfoChapter{
+		Count:       s.IssueCount,
+		Volume:      s.Volume,
+		Summary:     s.Desc,
+		Notes:       "Autogenerated with yuzu 🍋",
+		Publisher:   s.Publisher.Name,
+		Genre:       names(s.Genres),
+		Web:         s.ResourceURL,
+		LanguageISO: "en",
+		Manga:       "No",
+		ProviderID:  strconv.Itoa(s.ID),
+	}
+	if s.Imprint != nil {
+		ci.Imprint = s.Imprint.Name
+	}
+	standard.GetTitlePreference().Apply(ci, titles(s.Name))
+	return ci
+}
+
+func parseChapter(s *Series, issue *Issue) *standard.ComicInfoChapter {
+	ci := parseSeriesChapter(s)
+	ci.Title = cmp.Or(issue.Title, strings.Join(issue.Name, "; "))
+	ci.Number = issue.Number
+	ci.AlternateNumber = issue.AltNumber
+	ci.Summary = cmp.Or(issue.Desc, ci.Summary)
+	ci.PageCount = issue.Page
+	ci.AgeRating = ageRatings[issue.Rating.Name]
+	ci.GTIN = cmp.Or(issue.UPC, issue.ISBN)
+	ci.Characters = names(issue.Characters)
+	ci.Teams = names(issue.Teams)
+	ci.StoryArc = strings.Join(names(issue.Arcs), ",")
+	ci.Web = cmp.Or(issue.ResourceURL, ci.Web)
+	ci.ProviderID = strconv.Itoa(issue.ID)
+	if issue.Publisher.Name != "" {
+		ci.Publisher = issue.Publisher.Name
+	}
+	if t, err := time.Parse(time.DateOnly, issue.CoverDate); err == nil {
+		ci.Year, ci.Month, ci.Day = t.Year(), int(t.Month()), t.Day()
+	}
+
+	credits := map[string]*standard.List{
+		"Writer":      &ci.Writer,
+		"Penciller":   &ci.Penciller,
+		"Inker":       &ci.Inker,
+		"Colorist":    &ci.Colorist,
+		"Letterer":    &ci.Letterer,
+		"CoverArtist": &ci.CoverArtist,
+		"Editor":      &ci.Editor,
+		"Translator":  &ci.Translator,
+	}
+	for _, c := range issue.Credits {
+		for _, r := range c.Role {
+			for _, field := range roles[strings.ToLower(r.Name)] {
+				*credits[field] = credits[field].Add(c.Creator)
+			}
+		}
+	}
+
+	return ci
+}
+
+// statuses maps metron's series status to the standard series status
+var statuses = map[string]string{
+	"Ongoing":   standard.SeriesStatusOngoing,
+	"Completed": standard.SeriesStatusEnded,
+	"Limited":   standard.SeriesStatusEnded,
+	"Hiatus":    standard.SeriesStatusHiatus,
+	"Cancelled": standard.SeriesStatusAbandoned,
+}
+
+func parseSeries(s *Series) *standard.ComicInfoSeries {
+	cs := &standard.ComicInfoSeries{
+		Summary:     s.Desc,
+		Status:      statuses[s.Status],
+		Year:        s.YearBegan,
+		Count:       s.IssueCount,
+		Publisher:   s.Publisher.Name,
+		Genre:       names(s.Genres),
+		LanguageISO: "en",
+		Manga:       "No",
+		Web:         s.ResourceURL,
+		ProviderID:  strconv.Itoa(s.ID),
+	}
+	if s.Imprint != nil {
+		cs.Imprint = s.Imprint.Name
+	}
+	standard.GetTitlePreference().ApplySeries(cs, titles(s.Name))
+	return cs
+}
+
+// titles lists the title of a series, metron only knows the english one
+func titles(name string) []standard.Title {
+	return []standard.Title{{Lang: "en", Value: name}}
+}
+
+func names(named []Named) standard.List {
+	out := standard.List{}
+	for _, n := range named {
+		out = out.Add(n.Name)
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package metron
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/pkg/req/reqtest"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+func newProvider(t *testing.T) *MetronComicInfoProvider {
+	return NewMetronProvider(
+		reqtest.Client(t, "testdata", DefaultBaseURL),
+		os.Getenv("METRON_USERNAME"),
+		os.Getenv("METRON_PASSWORD"),
+	)
+}
+
+func TestProvideChapter(t *testing.T) {
+	want := &standard.ComicInfoChapter{
+		Title:       "Chapter One",
+		Series:      "Saga",
+		Number:      "1",
+		Count:       66,
+		Volume:      1,
+		Summary:     "Two soldiers from opposite sides of a never-ending galactic war fall in love.",
+		Notes:       "Autogenerated with yuzu 🍋",
+		Year:        2012,
+		Month:       3,
+		Day:         1,
+		Writer:      standard.List{"Brian K. Vaughan"},
+		Penciller:   standard.List{"Fiona Staples"},
+		Inker:       standard.List{"Fiona Staples"},
+		Colorist:    standard.List{"Fiona Staples"},
+		Letterer:    standard.List{"Fonografiks"},
+		CoverArtist: standard.List{"Fiona Staples"},
+		Editor:      standard.List{"Eric Stephenson"},
+		Publisher:   "Image",
+		Genre:       standard.List{"Science Fiction", "Fantasy"},
+		Web:         "https://metron.cloud/issue/saga-2012-1/",
+		PageCount:   44,
+		LanguageISO: "en",
+		Manga:       "No",
+		Characters:  standard.List{"Alana", "Marko", "Hazel"},
+		Teams:       standard.List{},
+		StoryArc:    "Chapter One",
+		AgeRating:   "Mature 17+",
+		GTIN:        "70985302780700111",
+		ProviderID:  "10413",
+	}
+	// file names pad the number, the api only knows "1"
+	p := newProvider(t)
+	for _, chapter := range []string{"1", "001"} {
+		got, err := p.ProvideChapter(context.Background(), "Saga", chapter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ProvideChapter(%q) =\n%+v\nwant\n%+v", chapter, got, want)
+		}
+	}
+}
+
+func TestProvideChapterMissingIssue(t *testing.T) {
+	got, err := newProvider(t).ProvideChapter(context.Background(), "Saga", "99")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the series still fills the chapter
+	if got.Series != "Saga" || got.Number != "" || got.ProviderID != "1502" {
+		t.Errorf("ProvideChapter() = %+v", got)
+	}
+}
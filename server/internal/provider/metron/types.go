@@ -0,0 +1,84 @@
+package metron
+
+// Named is the id and name pair metron uses for publishers, genres, arcs...
+type Named struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Page wraps every metron list response
+type Page[T any] struct {
+	Count   int    `json:"count"`
+	Next    string `json:"next"`
+	Results []T    `json:"results"`
+}
+
+// SeriesListItem is a series as returned by the series list endpoint
+type SeriesListItem struct {
+	ID int `json:"id"`
+	// Series is the display name, e.g. "Batman (2016)"
+	Series     string `json:"series"`
+	YearBegan  int    `json:"year_began"`
+	IssueCount int    `json:"issue_count"`
+	Volume     int    `json:"volume"`
+}
+
+type Series struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	SortName   string  `json:"sort_name"`
+	Volume     int     `json:"volume"`
+	SeriesType Named   `json:"series_type"`
+	Status     string  `json:"status"`
+	Publisher  Named   `json:"publisher"`
+	Imprint    *Named  `json:"imprint"`
+	YearBegan  int     `json:"year_began"`
+	YearEnd    int     `json:"year_end"`
+	Desc       string  `json:"desc"`
+	IssueCount int     `json:"issue_count"`
+	Genres     []Named `json:"genres"`
+	Associated []struct {
+		ID     int    `json:"id"`
+		Series string `json:"series"`
+	} `json:"associated"`
+	ResourceURL string `json:"resource_url"`
+}
+
+// IssueListItem is an issue as returned by the issue list endpoint
+type IssueListItem struct {
+	ID        int    `json:"id"`
+	Number    string `json:"number"`
+	CoverDate string `json:"cover_date"`
+}
+
+type Issue struct {
+	ID        int    `json:"id"`
+	Publisher Named  `json:"publisher"`
+	Imprint   *Named `json:"imprint"`
+	Series    struct {
+		ID        int     `json:"id"`
+		Name      string  `json:"name"`
+		Volume    int     `json:"volume"`
+		YearBegan int     `json:"year_began"`
+		Genres    []Named `json:"genres"`
+	} `json:"series"`
+	Number    string   `json:"number"`
+	AltNumber string   `json:"alt_number"`
+	Title     string   `json:"title"`
+	Name      []string `json:"name"`
+	CoverDate string   `json:"cover_date"`
+	Rating    Named    `json:"rating"`
+	ISBN      string   `json:"isbn"`
+	UPC       string   `json:"upc"`
+	Page      int      `json:"page"`
+	Desc      string   `json:"desc"`
+	Image     string   `json:"image"`
+	Arcs      []Named  `json:"arcs"`
+	Credits   []struct {
+		Creator string  `json:"creator"`
+		Role    []Named `json:"role"`
+	} `json:"credits"`
+	Characters  []Named `json:"characters"`
+	Teams       []Named `json:"teams"`
+	ResourceURL string  `json:"resource_url"`
+}
@@ -13,23 +13,111 @@ import (
 	"github.com/vyxn/yuzu/internal/pkg/assert"
 	"github.com/vyxn/yuzu/internal/pkg/req"
 	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider"
 	"github.com/vyxn/yuzu/internal/standard"
 )
 
-const baseURL = "https://api.myanimelist.net/v2/manga"
+// DefaultBaseURL is the api used by the registered provider
+const DefaultBaseURL = "https://api.myanimelist.net/v2"
+
+const mangaPath = "/manga"
+
+type MangaInfo struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	MainPicture struct {
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"main_picture"`
+	AlternativeTitles struct {
+		Synonyms []string `json:"synonyms"`
+		En       string   `json:"en"`
+		Ja       string   `json:"ja"`
+	} `json:"alternative_titles"`
+	StartDate       string    `json:"start_date"`
+	Synopsis        string    `json:"synopsis"`
+	Mean            float64   `json:"mean"`
+	Rank            int       `json:"rank"`
+	Popularity      int       `json:"popularity"`
+	NumListUsers    int       `json:"num_list_users"`
+	NumScoringUsers int       `json:"num_scoring_users"`
+	Nsfw            string    `json:"nsfw"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	MediaType       string    `json:"media_type"`
+	Status          string    `json:"status"`
+	Genres          []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"genres"`
+	NumVolumes  int `json:"num_volumes"`
+	NumChapters int `json:"num_chapters"`
+	Authors     []struct {
+		Node struct {
+			ID        int    `json:"id"`
+			FirstName string `json:"first_name"`
+			LastName  string `json:"last_name"`
+		} `json:"node"`
+		Role string `json:"role"`
+	} `json:"authors"`
+	// Pictures []struct {
+	// 	Medium string `json:"medium"`
+	// 	Large  string `json:"large"`
+	// } `json:"pictures"`
+	Background    string `json:"background"`
+	Serialization []struct {
+		Node struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"node"`
+	} `json:"serialization"`
+}
 
 type MyAnimeListComicInfoProvider struct {
+	client   *req.Client
 	clientID string
 }
 
-func NewMyAnimeListProvider(clientID string) *MyAnimeListComicInfoProvider {
-	assert.Assert(
-		clientID != "",
-		"configure env MYANIMELIST_CLIENT_ID to use this provider",
-	)
-	return &MyAnimeListComicInfoProvider{clientID}
+func init() {
+	provider.Register(provider.Registration{
+		Name:        "myanimelist",
+		Credentials: []string{"MYANIMELIST_CLIENT_ID"},
+		Capabilities: []provider.Capability{
+			provider.CapabilityChapter,
+			provider.CapabilitySearch,
+			provider.CapabilityByID,
+			provider.CapabilitySeries,
+		},
+		Priority: 20,
+		New: func(
+			credentials map[string]string,
+		) (provider.ComicInfoProvider, error) {
+			return NewMyAnimeListProvider(
+				nil,
+				credentials["MYANIMELIST_CLIENT_ID"],
+			)
+		},
+	})
+}
+
+// NewMyAnimeListProvider calls the api with client, nil uses DefaultBaseURL
+func NewMyAnimeListProvider(
+	client *req.Client,
+	clientID string,
+) (*MyAnimeListComicInfoProvider, error) {
+	if clientID == "" {
+		return nil, yerr.WithStackf(
+			"configure env MYANIMELIST_CLIENT_ID to use this provider",
+		)
+	}
+	if client == nil {
+		client = req.NewClient(DefaultBaseURL, nil)
+	}
+	return &MyAnimeListComicInfoProvider{client, clientID}, nil
 }
 
+func (p *MyAnimeListComicInfoProvider) Name() string { return "myanimelist" }
+
 func (p *MyAnimeListComicInfoProvider) ProvideChapter(
 	ctx context.Context, series, chapter string,
 ) (*standard.ComicInfoChapter, error) {
@@ -41,38 +129,42 @@ func (p *MyAnimeListComicInfoProvider) ProvideChapter(
 	return res, nil
 }
 
-func (p *MyAnimeListComicInfoProvider) getBestMatchID(
-	ctx context.Context,
-	series string,
-) (string, error) {
-	u, err := url.Parse(baseURL)
+func (p *MyAnimeListComicInfoProvider) ProvideSeries(
+	ctx context.Context, series string,
+) (*standard.ComicInfoSeries, error) {
+	res, err := p.getMangaInfo(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get MAL series info: %w", err)
+	}
+
+	return parseSeries(res), nil
+}
+
+func (p *MyAnimeListComicInfoProvider) SearchSeries(
+	ctx context.Context, series string,
+) ([]provider.SeriesCandidate, error) {
+	u, err := url.Parse(p.client.URL(mangaPath))
 	if err != nil {
-		return "", yerr.WithStackf("parsing url %s: %w", baseURL, err)
+		return nil, yerr.WithStackf("parsing url %s: %w", mangaPath, err)
 	}
 
 	params := url.Values{}
 	params.Add("q", series)
+	params.Add("fields", "alternative_titles,start_date,media_type")
 	u.RawQuery = params.Encode()
 
-	data, err := req.Get(
+	data, err := p.client.Get(
 		ctx,
 		u.String(),
 		map[string]string{"X-MAL-CLIENT-ID": p.clientID},
 	)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	type ListResult struct {
 		Data []struct {
-			Node struct {
-				ID          int    `json:"id"`
-				Title       string `json:"title"`
-				MainPicture struct {
-					Medium string `json:"medium"`
-					Large  string `json:"large"`
-				} `json:"main_picture"`
-			} `json:"node"`
+			Node MangaInfo `json:"node"`
 		} `json:"data"`
 		Paging struct {
 			Next string `json:"next"`
@@ -80,30 +172,66 @@ func (p *MyAnimeListComicInfoProvider) getBestMatchID(
 	}
 	var res ListResult
 	if err := json.Unmarshal(data, &res); err != nil {
-		return "", yerr.WithStackf("unmarshalling json: %w", err)
+		return nil, yerr.WithStackf("unmarshalling json: %w", err)
 	}
 
+	candidates := make([]provider.SeriesCandidate, 0, len(res.Data))
 	for _, m := range res.Data {
-		return strconv.Itoa(m.Node.ID), nil
+		c := provider.SeriesCandidate{
+			ProviderID: strconv.Itoa(m.Node.ID),
+			Titles:     titles(&m.Node),
+			Type:       m.Node.MediaType,
+			CoverURL:   m.Node.MainPicture.Large,
+		}
+		if len(m.Node.StartDate) >= 4 {
+			c.Year, _ = strconv.Atoi(m.Node.StartDate[:4])
+		}
+		candidates = append(candidates, c)
 	}
 
-	return "", yerr.WithStackf("not found")
+	return provider.Rank(series, candidates), nil
 }
 
-func (p *MyAnimeListComicInfoProvider) getComicInfo(
+func (p *MyAnimeListComicInfoProvider) ProvideChapterByID(
+	ctx context.Context, id, chapter string,
+) (*standard.ComicInfoChapter, error) {
+	if err := provider.CheckNumericID(id); err != nil {
+		return nil, err
+	}
+
+	res, err := p.getMangaInfoByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get MAL comicinfo: %w", err)
+	}
+
+	return parseChapter(res), nil
+}
+
+func (p *MyAnimeListComicInfoProvider) getMangaInfo(
 	ctx context.Context,
 	series string,
-) (*standard.ComicInfoChapter, error) {
-	id, err := p.getBestMatchID(ctx, series)
+) (*MangaInfo, error) {
+	candidates, err := p.SearchSeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("finding series: %w", err)
+	}
+	best, err := provider.Best(series, candidates)
 	if err != nil {
 		return nil, fmt.Errorf("finding series: %w", err)
 	}
 
+	return p.getMangaInfoByID(ctx, best.ProviderID)
+}
+
+func (p *MyAnimeListComicInfoProvider) getMangaInfoByID(
+	ctx context.Context,
+	id string,
+) (*MangaInfo, error) {
 	assert.Assert(id != "", "MAL returned empty id")
 
-	u, err := url.Parse(baseURL)
+	u, err := url.Parse(p.client.URL(mangaPath))
 	if err != nil {
-		return nil, yerr.WithStackf("parsing url %s: %w", baseURL, err)
+		return nil, yerr.WithStackf("parsing url %s: %w", mangaPath, err)
 	}
 
 	u.Path = path.Join(u.Path, id)
@@ -115,7 +243,7 @@ func (p *MyAnimeListComicInfoProvider) getComicInfo(
 	)
 	u.RawQuery = params.Encode()
 
-	data, err := req.Get(
+	data, err := p.client.Get(
 		ctx,
 		u.String(),
 		map[string]string{"X-MAL-CLIENT-ID": p.clientID},
@@ -124,65 +252,87 @@ func (p *MyAnimeListComicInfoProvider) getComicInfo(
 		return nil, err
 	}
 
-	type MangaInfo struct {
-		ID          int    `json:"id"`
-		Title       string `json:"title"`
-		MainPicture struct {
-			Medium string `json:"medium"`
-			Large  string `json:"large"`
-		} `json:"main_picture"`
-		AlternativeTitles struct {
-			Synonyms []string `json:"synonyms"`
-			En       string   `json:"en"`
-			Ja       string   `json:"ja"`
-		} `json:"alternative_titles"`
-		StartDate       string    `json:"start_date"`
-		Synopsis        string    `json:"synopsis"`
-		Mean            float64   `json:"mean"`
-		Rank            int       `json:"rank"`
-		Popularity      int       `json:"popularity"`
-		NumListUsers    int       `json:"num_list_users"`
-		NumScoringUsers int       `json:"num_scoring_users"`
-		Nsfw            string    `json:"nsfw"`
-		CreatedAt       time.Time `json:"created_at"`
-		UpdatedAt       time.Time `json:"updated_at"`
-		MediaType       string    `json:"media_type"`
-		Status          string    `json:"status"`
-		Genres          []struct {
-			ID   int    `json:"id"`
-			Name string `json:"name"`
-		} `json:"genres"`
-		NumVolumes  int `json:"num_volumes"`
-		NumChapters int `json:"num_chapters"`
-		Authors     []struct {
-			Node struct {
-				ID        int    `json:"id"`
-				FirstName string `json:"first_name"`
-				LastName  string `json:"last_name"`
-			} `json:"node"`
-			Role string `json:"role"`
-		} `json:"authors"`
-		// Pictures []struct {
-		// 	Medium string `json:"medium"`
-		// 	Large  string `json:"large"`
-		// } `json:"pictures"`
-		Background    string `json:"background"`
-		Serialization []struct {
-			Node struct {
-				ID   int    `json:"id"`
-				Name string `json:"name"`
-			} `json:"node"`
-		} `json:"serialization"`
-	}
 	var res MangaInfo
 	if err := json.Unmarshal(data, &res); err != nil {
 		return nil, yerr.WithStackf("unmarshalling json: %w", err)
 	}
 
-	return &standard.ComicInfoChapter{
-		Series:  res.Title,
-		Summary: res.Synopsis,
-		Notes:   "Autogenerated with yuzu 🍋",
-		Manga:   "YesAndRightToLeft",
-	}, nil
+	return &res, nil
+}
+
+func (p *MyAnimeListComicInfoProvider) getComicInfo(
+	ctx context.Context,
+	series string,
+) (*standard.ComicInfoChapter, error) {
+	res, err := p.getMangaInfo(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseChapter(res), nil
+}
+
+func parseChapter(res *MangaInfo) *standard.ComicInfoChapter {
+	ci := &standard.ComicInfoChapter{
+		Summary:    res.Synopsis,
+		Notes:      "Autogenerated with yuzu 🍋",
+		Manga:      "YesAndRightToLeft",
+		ProviderID: strconv.Itoa(res.ID),
+	}
+	standard.GetTitlePreference().Apply(ci, titles(res))
+
+	return ci
+}
+
+// statuses maps MAL's manga status to the standard series status
+var statuses = map[string]string{
+	"currently_publishing": standard.SeriesStatusOngoing,
+	"finished":             standard.SeriesStatusEnded,
+	"on_hiatus":            standard.SeriesStatusHiatus,
+	"discontinued":         standard.SeriesStatusAbandoned,
+	"not_yet_published":    standard.SeriesStatusUnknown,
+}
+
+func parseSeries(res *MangaInfo) *standard.ComicInfoSeries {
+	genres := standard.List{}
+	for _, g := range res.Genres {
+		genres = genres.Add(g.Name)
+	}
+
+	cs := &standard.ComicInfoSeries{
+		Summary:         res.Synopsis,
+		Status:          statuses[res.Status],
+		Count:           res.NumChapters,
+		VolumeCount:     res.NumVolumes,
+		Genre:           genres,
+		Manga:           "YesAndRightToLeft",
+		CommunityRating: res.Mean / 2,
+		CoverURL:        res.MainPicture.Large,
+		Web:             fmt.Sprintf("https://myanimelist.net/manga/%d", res.ID),
+		ProviderID:      strconv.Itoa(res.ID),
+	}
+
+	standard.GetTitlePreference().ApplySeries(cs, titles(res))
+
+	if len(res.Serialization) > 0 {
+		cs.Publisher = res.Serialization[0].Node.Name
+	}
+	if len(res.StartDate) >= 4 {
+		cs.Year, _ = strconv.Atoi(res.StartDate[:4])
+	}
+
+	return cs
+}
+
+// titles lists every title of the manga, MAL's main title is romanized
+func titles(res *MangaInfo) []standard.Title {
+	titles := []standard.Title{
+		{Lang: "ja-Latn", Value: res.Title},
+		{Lang: "en", Value: res.AlternativeTitles.En},
+		{Lang: "ja", Value: res.AlternativeTitles.Ja},
+	}
+	for _, s := range res.AlternativeTitles.Synonyms {
+		titles = append(titles, standard.Title{Value: s})
+	}
+	return titles
 }
@@ -0,0 +1,56 @@
+package myanimelist
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/pkg/req/reqtest"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// newProvider replays the fixtures, the client id only matters with -record
+func newProvider(t *testing.T) *MyAnimeListComicInfoProvider {
+	p, err := NewMyAnimeListProvider(
+		reqtest.Client(t, "testdata", DefaultBaseURL),
+		cmp.Or(os.Getenv("MYANIMELIST_CLIENT_ID"), "test"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestProvideChapter(t *testing.T) {
+	got, err := newProvider(t).ProvideChapter(context.Background(), "Berserk", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &standard.ComicInfoChapter{
+		Series:          "Berserk",
+		AlternateSeries: "ベルセルク",
+		Summary:         "Guts, a former mercenary now known as the \"Black Swordsman,\" is out for revenge.",
+		Notes:           "Autogenerated with yuzu 🍋",
+		LanguageISO:     "en",
+		Manga:           "YesAndRightToLeft",
+		ProviderID:      "2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProvideChapter() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestProvideSeries(t *testing.T) {
+	got, err := newProvider(t).ProvideSeries(context.Background(), "Berserk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Status != standard.SeriesStatusHiatus || got.Year != 1989 ||
+		got.Publisher != "Young Animal" || got.CommunityRating != 4.735 {
+		t.Errorf("ProvideSeries() = %+v", got)
+	}
+}
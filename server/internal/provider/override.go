@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// OverrideSource names manual overrides in provenance and comparisons
+const OverrideSource = "override"
+
+// Override is a manual correction of the merged metadata
+type Override struct {
+	// Fields holds the corrected values, zero fields are left to providers
+	Fields *standard.ComicInfoChapter
+	// Locked fields take the value of Fields as is, even when it is zero, so
+	// providers never change them
+	Locked []string
+}
+
+// Apply puts o on top of ci, non-zero fields replace the provider values,
+// lists included, and provenance is updated to match
+func (o Override) Apply(ci *standard.ComicInfoChapter, provenance Provenance) {
+	fields := o.Fields
+	if fields == nil {
+		fields = &standard.ComicInfoChapter{}
+	}
+	source := []Source{{Provider: OverrideSource}}
+
+	dv := reflect.ValueOf(ci).Elem()
+	ov := reflect.ValueOf(fields).Elem()
+	t := dv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		df, of := dv.Field(i), ov.Field(i)
+		if !df.CanSet() || name == "XMLName" || name == "ProviderID" {
+			continue
+		}
+
+		switch {
+		case slices.Contains(o.Locked, name):
+			df.Set(of)
+			if of.IsZero() {
+				delete(provenance, name)
+			} else {
+				provenance[name] = source
+			}
+		case of.IsZero():
+		default:
+			df.Set(of)
+			provenance[name] = source
+		}
+	}
+}
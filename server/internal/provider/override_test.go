@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+func TestOverrideApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		override Override
+		genre    standard.List
+		source   string
+	}{
+		{
+			"list replaces the provider values",
+			Override{Fields: &standard.ComicInfoChapter{Genre: standard.List{"Drama"}}},
+			standard.List{"Drama"},
+			OverrideSource,
+		},
+		{
+			"empty list keeps the provider values",
+			Override{Fields: &standard.ComicInfoChapter{}},
+			standard.List{"Action", "Comedy"},
+			"kitsu",
+		},
+		{
+			"locked empty list clears the provider values",
+			Override{Fields: &standard.ComicInfoChapter{}, Locked: []string{"Genre"}},
+			nil,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ci := &standard.ComicInfoChapter{Genre: standard.List{"Action", "Comedy"}}
+			provenance := Provenance{"Genre": {{Provider: "kitsu"}}}
+
+			tt.override.Apply(ci, provenance)
+
+			if !slices.Equal(ci.Genre, tt.genre) {
+				t.Errorf("Genre = %q, want %q", ci.Genre, tt.genre)
+			}
+			source := ""
+			if s := provenance["Genre"]; len(s) > 0 {
+				source = s[0].Provider
+			}
+			if source != tt.source {
+				t.Errorf("Genre provenance = %q, want %q", source, tt.source)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"math"
+	"reflect"
+	"slices"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// MergePolicyConfigKey is where the merge policy is stored in the config table
+const MergePolicyConfigKey = "merge"
+
+// Strategy decides how the values of several providers become one
+type Strategy string
+
+const (
+	// StrategyFirst takes the first non-zero value in priority order
+	StrategyFirst Strategy = "first"
+	// StrategyUnion joins every value of multi-valued fields
+	StrategyUnion Strategy = "union"
+	// StrategyAverage averages every non-zero numeric value, providers are
+	// expected to normalize their ratings to the ComicInfo 0-5 range
+	StrategyAverage Strategy = "average"
+)
+
+// FieldPolicy is the merge policy of a single ComicInfo field
+type FieldPolicy struct {
+	Strategy Strategy `json:"strategy"`
+	// Priority lists provider names tried first, providers not listed follow
+	// in the order they were given
+	Priority []string `json:"priority,omitempty"`
+}
+
+// MergePolicy maps ComicInfo field names to their FieldPolicy, fields without
+// an entry use Default
+type MergePolicy struct {
+	Default FieldPolicy            `json:"default"`
+	Fields  map[string]FieldPolicy `json:"fields"`
+}
+
+var creditFields = []string{
+	"Writer",
+	"Penciller",
+	"Inker",
+	"Colorist",
+	"Letterer",
+	"CoverArtist",
+	"Editor",
+	"Translator",
+}
+
+// DefaultMergePolicy is used when the config table has no policy
+func DefaultMergePolicy() MergePolicy {
+	p := MergePolicy{
+		Default: FieldPolicy{Strategy: StrategyFirst},
+		Fields: map[string]FieldPolicy{
+			"Summary": {
+				Strategy: StrategyFirst,
+				Priority: []string{"myanimelist"},
+			},
+			"Genre":           {Strategy: StrategyUnion},
+			"Tags":            {Strategy: StrategyUnion},
+			"Characters":      {Strategy: StrategyUnion},
+			"Teams":           {Strategy: StrategyUnion},
+			"Locations":       {Strategy: StrategyUnion},
+			"CommunityRating": {Strategy: StrategyAverage},
+		},
+	}
+	for _, f := range creditFields {
+		p.Fields[f] = FieldPolicy{
+			Strategy: StrategyFirst,
+			Priority: []string{"metron", "comicvine", "anilist"},
+		}
+	}
+	return p
+}
+
+// Validate checks every field name and that every strategy fits the type of
+// its field, the default applies to fields of every type so it can only be
+// StrategyFirst
+func (p MergePolicy) Validate() error {
+	if err := p.Default.validate("default", nil); err != nil {
+		return err
+	}
+
+	t := reflect.TypeFor[standard.ComicInfoChapter]()
+	for name, fp := range p.Fields {
+		f, ok := t.FieldByName(name)
+		if !ok || !f.IsExported() {
+			return yerr.WithStackf("unknown comicinfo field <%s>", name)
+		}
+		if err := fp.validate(name, f.Type); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate checks that the strategy of fp applies to fields of type t, a nil
+// t stands for fields of any type
+func (fp FieldPolicy) validate(field string, t reflect.Type) error {
+	fits := false
+	switch fp.Strategy {
+	case StrategyFirst:
+		fits = true
+	case StrategyUnion:
+		fits = t == listType
+	case StrategyAverage:
+		fits = t != nil && isNumeric(t.Kind())
+	default:
+		return yerr.WithStackf(
+			"unknown strategy <%s> for field <%s>",
+			fp.Strategy,
+			field,
+		)
+	}
+	if !fits {
+		return yerr.WithStackf(
+			"strategy <%s> doesn't apply to field <%s>",
+			fp.Strategy,
+			field,
+		)
+	}
+	return nil
+}
+
+func isNumeric(k reflect.Kind) bool {
+	switch k {
+	case reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// Field returns the policy of the named field
+func (p MergePolicy) Field(name string) FieldPolicy {
+	if fp, ok := p.Fields[name]; ok {
+		return fp
+	}
+	return p.Default
+}
+
+// ProviderResult is the metadata a named provider returned
+type ProviderResult struct {
+	Provider string
+	Chapter  *standard.ComicInfoChapter
+}
+
+// Merge combines results field by field following p and records which
+// provider supplied each populated field
+func (p MergePolicy) Merge(
+	results []ProviderResult,
+) (*standard.ComicInfoChapter, Provenance) {
+	out := &standard.ComicInfoChapter{}
+	provenance := Provenance{}
+	dv := reflect.ValueOf(out).Elem()
+	t := dv.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		df := dv.Field(i)
+		name := t.Field(i).Name
+		if !df.CanSet() || name == "XMLName" || name == "ProviderID" {
+			continue
+		}
+
+		fp := p.Field(name)
+		values := []reflect.Value{}
+		sources := []Source{}
+		for _, r := range fp.order(results) {
+			sf := reflect.ValueOf(r.Chapter).Elem().Field(i)
+			if !sf.IsZero() {
+				values = append(values, sf)
+				sources = append(sources, Source{
+					Provider:   r.Provider,
+					ProviderID: r.Chapter.ProviderID,
+				})
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		v, used := mergeValues(fp.Strategy, values)
+		df.Set(v)
+		provenance[name] = used(sources)
+	}
+
+	return out, provenance
+}
+
+// order sorts results by fp.Priority, keeping the given order otherwise
+func (fp FieldPolicy) order(results []ProviderResult) []ProviderResult {
+	rank := func(r ProviderResult) int {
+		if i := slices.Index(fp.Priority, r.Provider); i >= 0 {
+			return i
+		}
+		return len(fp.Priority)
+	}
+
+	out := slices.Clone(results)
+	slices.SortStableFunc(out, func(a, b ProviderResult) int {
+		return rank(a) - rank(b)
+	})
+	return out
+}
+
+// mergeValues applies strategy to the non-zero values of a field, strategies
+// that don't apply to the field's type fall back to StrategyFirst, the
+// returned func picks the sources of the values that ended up being used
+func mergeValues(
+	strategy Strategy,
+	values []reflect.Value,
+) (reflect.Value, func([]Source) []Source) {
+	all := func(s []Source) []Source { return s }
+
+	switch strategy {
+	case StrategyUnion:
+		if values[0].Type() != listType {
+			break
+		}
+		union := standard.List{}
+		contributed := []int{}
+		for i, v := range values {
+			before := len(union)
+			union = union.Union(v.Interface().(standard.List))
+			if len(union) > before {
+				contributed = append(contributed, i)
+			}
+		}
+		return reflect.ValueOf(union), func(s []Source) []Source {
+			out := []Source{}
+			for _, i := range contributed {
+				out = append(out, s[i])
+			}
+			return out
+		}
+
+	case StrategyAverage:
+		sum := 0.0
+		switch values[0].Kind() {
+		case reflect.Float32, reflect.Float64:
+			for _, v := range values {
+				sum += v.Float()
+			}
+			avg := reflect.New(values[0].Type()).Elem()
+			avg.SetFloat(sum / float64(len(values)))
+			return avg, all
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			for _, v := range values {
+				sum += float64(v.Int())
+			}
+			avg := reflect.New(values[0].Type()).Elem()
+			avg.SetInt(int64(math.Round(sum / float64(len(values)))))
+			return avg, all
+		}
+	}
+
+	return values[0], func(s []Source) []Source { return s[:1] }
+}
@@ -0,0 +1,82 @@
+package provider
+
+import "testing"
+
+func TestMergePolicyValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy MergePolicy
+		valid  bool
+	}{
+		{"default policy", DefaultMergePolicy(), true},
+		{
+			"union of a list",
+			MergePolicy{
+				Default: FieldPolicy{Strategy: StrategyFirst},
+				Fields:  map[string]FieldPolicy{"Genre": {Strategy: StrategyUnion}},
+			},
+			true,
+		},
+		{
+			"average of an int",
+			MergePolicy{
+				Default: FieldPolicy{Strategy: StrategyFirst},
+				Fields:  map[string]FieldPolicy{"Count": {Strategy: StrategyAverage}},
+			},
+			true,
+		},
+		{
+			"union of a string",
+			MergePolicy{
+				Default: FieldPolicy{Strategy: StrategyFirst},
+				Fields:  map[string]FieldPolicy{"Summary": {Strategy: StrategyUnion}},
+			},
+			false,
+		},
+		{
+			"average of a string",
+			MergePolicy{
+				Default: FieldPolicy{Strategy: StrategyFirst},
+				Fields:  map[string]FieldPolicy{"Title": {Strategy: StrategyAverage}},
+			},
+			false,
+		},
+		{
+			"average of a list",
+			MergePolicy{
+				Default: FieldPolicy{Strategy: StrategyFirst},
+				Fields:  map[string]FieldPolicy{"Tags": {Strategy: StrategyAverage}},
+			},
+			false,
+		},
+		{
+			"union by default",
+			MergePolicy{Default: FieldPolicy{Strategy: StrategyUnion}},
+			false,
+		},
+		{
+			"unknown strategy",
+			MergePolicy{Default: FieldPolicy{Strategy: "last"}},
+			false,
+		},
+		{
+			"unknown field",
+			MergePolicy{
+				Default: FieldPolicy{Strategy: StrategyFirst},
+				Fields:  map[string]FieldPolicy{"Author": {Strategy: StrategyFirst}},
+			},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Validate() = nil, want an error")
+			}
+		})
+	}
+}
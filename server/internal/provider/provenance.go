@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Source is the provider, and the entity at that provider, a value came from
+type Source struct {
+	Provider   string `json:"provider"`
+	ProviderID string `json:"providerID,omitempty"`
+}
+
+func (s Source) String() string {
+	if s.ProviderID == "" {
+		return s.Provider
+	}
+	return fmt.Sprintf("%s (%s)", s.Provider, s.ProviderID)
+}
+
+// Provenance maps every populated ComicInfo field to the sources of its value
+type Provenance map[string][]Source
+
+// Summary lists the fields grouped by the sources that supplied them, in a
+// form short enough for the Notes field
+func (p Provenance) Summary() string {
+	bySource := map[string][]string{}
+	for field, sources := range p {
+		for _, s := range sources {
+			bySource[s.String()] = append(bySource[s.String()], field)
+		}
+	}
+
+	keys := make([]string, 0, len(bySource))
+	for k := range bySource {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		fields := bySource[k]
+		slices.Sort(fields)
+		lines = append(lines, k+": "+strings.Join(fields, ", "))
+	}
+
+	return "Sources:\n" + strings.Join(lines, "\n")
+}
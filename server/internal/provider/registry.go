@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"cmp"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+)
+
+// ProvidersConfigKey is where provider settings are stored in the config table
+const ProvidersConfigKey = "providers"
+
+// Capability is something a provider is able to do
+type Capability string
+
+const (
+	CapabilityChapter Capability = "chapter"
+	CapabilitySearch  Capability = "search"
+	CapabilityByID    Capability = "byID"
+	CapabilitySeries  Capability = "series"
+)
+
+// Registration describes a provider to the registry
+type Registration struct {
+	Name string
+	// Credentials lists the environment variables the provider can't work
+	// without
+	Credentials  []string
+	Capabilities []Capability
+	// Priority orders providers when the config table doesn't, lower first
+	Priority int
+	// New builds the provider, credentials are keyed by environment variable
+	New func(credentials map[string]string) (ComicInfoProvider, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Registration{}
+)
+
+// Register makes a provider available, it is meant to be called from the init
+// function of the provider package and panics when name is taken
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[r.Name]; ok {
+		panic("provider: Register called twice for " + r.Name)
+	}
+	registry[r.Name] = r
+}
+
+// ProviderSettings is the config of a single provider
+type ProviderSettings struct {
+	// Enabled defaults to true when unset
+	Enabled *bool `json:"enabled,omitempty"`
+	// Priority overrides Registration.Priority when set
+	Priority *int `json:"priority,omitempty"`
+}
+
+// ProvidersConfig maps provider names to their settings
+type ProvidersConfig map[string]ProviderSettings
+
+// Validate checks that every configured provider is registered
+func (c ProvidersConfig) Validate() error {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for name := range c {
+		if _, ok := registry[name]; !ok {
+			return yerr.WithStackf("unknown provider <%s>", name)
+		}
+	}
+	return nil
+}
+
+// Status reports whether a registered provider can be used
+type Status struct {
+	Name         string       `json:"name"`
+	Capabilities []Capability `json:"capabilities"`
+	Credentials  []string     `json:"credentials,omitempty"`
+	Priority     int          `json:"priority"`
+	Enabled      bool         `json:"enabled"`
+	// Available is false when credentials are missing or the provider
+	// couldn't be built, Reason tells why
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+
+	provider ComicInfoProvider
+}
+
+// Statuses builds every registered provider with the settings of c, ordered
+// by priority then name
+func (c ProvidersConfig) Statuses() []Status {
+	registryMu.RLock()
+	regs := make([]Registration, 0, len(registry))
+	for _, r := range registry {
+		regs = append(regs, r)
+	}
+	registryMu.RUnlock()
+
+	out := make([]Status, 0, len(regs))
+	for _, r := range regs {
+		s := Status{
+			Name:         r.Name,
+			Capabilities: r.Capabilities,
+			Credentials:  r.Credentials,
+			Priority:     r.Priority,
+			Enabled:      true,
+		}
+		if set, ok := c[r.Name]; ok {
+			if set.Enabled != nil {
+				s.Enabled = *set.Enabled
+			}
+			if set.Priority != nil {
+				s.Priority = *set.Priority
+			}
+		}
+
+		credentials := map[string]string{}
+		missing := []string{}
+		for _, env := range r.Credentials {
+			if v := os.Getenv(env); v != "" {
+				credentials[env] = v
+			} else {
+				missing = append(missing, env)
+			}
+		}
+
+		switch {
+		case len(missing) > 0:
+			s.Reason = "missing credentials " + strings.Join(missing, ", ")
+		case !s.Enabled:
+			s.Reason = "disabled"
+		default:
+			p, err := r.New(credentials)
+			if err != nil {
+				s.Reason = err.Error()
+				break
+			}
+			s.Available = true
+			s.provider = p
+		}
+
+		out = append(out, s)
+	}
+
+	slices.SortFunc(out, func(a, b Status) int {
+		return cmp.Or(
+			cmp.Compare(a.Priority, b.Priority),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return out
+}
+
+// Providers returns the enabled and available providers in priority order,
+// providers are built anew on every call, responses are cached by the caller
+func (c ProvidersConfig) Providers() []ComicInfoProvider {
+	out := []ComicInfoProvider{}
+	for _, s := range c.Statuses() {
+		if s.Available {
+			out = append(out, s.provider)
+		}
+	}
+	return out
+}
+
+// Provider returns the named provider, or an error telling why it can't be
+// used
+func (c ProvidersConfig) Provider(name string) (ComicInfoProvider, error) {
+	for _, s := range c.Statuses() {
+		if s.Name != name {
+			continue
+		}
+		if !s.Available {
+			return nil, yerr.WithStackf(
+				"provider <%s> is unavailable: %s",
+				name,
+				s.Reason,
+			)
+		}
+		return s.provider, nil
+	}
+	return nil, yerr.WithStackf("unknown provider <%s>", name)
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+const testCredential = "YUZU_TEST_REGISTRY_KEY"
+
+func init() {
+	register := func(name string, priority int, credentials ...string) {
+		Register(Registration{
+			Name:         name,
+			Credentials:  credentials,
+			Capabilities: []Capability{CapabilityChapter},
+			Priority:     priority,
+			New: func(map[string]string) (ComicInfoProvider, error) {
+				return returning(name, &standard.ComicInfoChapter{}), nil
+			},
+		})
+	}
+	register("registry-b", 10)
+	register("registry-a", 10)
+	register("registry-first", 1)
+	register("registry-keyed", 5, testCredential)
+	Register(Registration{
+		Name:     "registry-broken",
+		Priority: 1,
+		New: func(map[string]string) (ComicInfoProvider, error) {
+			return nil, errors.New("broken")
+		},
+	})
+}
+
+// names lists the providers registered by this file in the order of ps
+func names(ps []ComicInfoProvider) []string {
+	out := []string{}
+	for _, p := range ps {
+		if strings.HasPrefix(p.Name(), "registry-") {
+			out = append(out, p.Name())
+		}
+	}
+	return out
+}
+
+func TestProvidersConfigProviders(t *testing.T) {
+	disabled, last := false, 100
+
+	tests := []struct {
+		name       string
+		cfg        ProvidersConfig
+		credential string
+		want       []string
+	}{
+		{
+			"priority then name",
+			nil,
+			"",
+			[]string{"registry-first", "registry-a", "registry-b"},
+		},
+		{
+			"credentials set",
+			nil,
+			"secret",
+			[]string{"registry-first", "registry-keyed", "registry-a", "registry-b"},
+		},
+		{
+			"config overrides",
+			ProvidersConfig{
+				"registry-first": {Priority: &last},
+				"registry-b":     {Enabled: &disabled},
+			},
+			"",
+			[]string{"registry-a", "registry-first"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(testCredential, tt.credential)
+			if got := names(tt.cfg.Providers()); !slices.Equal(got, tt.want) {
+				t.Errorf("Providers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvidersConfigProvider(t *testing.T) {
+	t.Setenv(testCredential, "")
+	cfg := ProvidersConfig{}
+
+	if p, err := cfg.Provider("registry-a"); err != nil || p.Name() != "registry-a" {
+		t.Errorf("Provider(registry-a) = %v, %v", p, err)
+	}
+	for _, name := range []string{"registry-keyed", "registry-broken", "unknown"} {
+		if _, err := cfg.Provider(name); err == nil {
+			t.Errorf("Provider(%q) = nil error", name)
+		}
+	}
+
+	for _, s := range cfg.Statuses() {
+		if s.Name == "registry-keyed" &&
+			(s.Available || s.Reason != "missing credentials "+testCredential) {
+			t.Errorf("status of registry-keyed = %+v", s)
+		}
+	}
+}
+
+func TestProvidersConfigValidate(t *testing.T) {
+	if err := (ProvidersConfig{"registry-a": {}}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	if err := (ProvidersConfig{"unknown": {}}).Validate(); err == nil {
+		t.Error("Validate() of an unknown provider = nil error")
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a taken name didn't panic")
+		}
+	}()
+	Register(Registration{Name: "registry-a"})
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/vyxn/yuzu/internal/pkg/req"
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+	"github.com/vyxn/yuzu/internal/provider/match"
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+// ErrNotFound is wrapped when a provider has no such series or chapter, the
+// 404 responses of its api wrap it too
+var ErrNotFound = req.ErrNotFound
+
+// MinScore is the score below which a candidate isn't considered a match
+const MinScore = 0.5
+
+// SeriesCandidate is a series returned by a provider search
+type SeriesCandidate struct {
+	ProviderID string           `json:"providerID"`
+	Titles     []standard.Title `json:"titles"`
+	Year       int              `json:"year,omitempty"`
+	Type       string           `json:"type,omitempty"`
+	CoverURL   string           `json:"coverURL,omitempty"`
+	Score      float64          `json:"score"`
+}
+
+// Rank scores every candidate against the series folder name with the
+// fuzzy matcher and sorts them best first, candidates with the same score keep
+// the order of the provider
+func Rank(series string, candidates []SeriesCandidate) []SeriesCandidate {
+	q := match.ParseQuery(series)
+	for i := range candidates {
+		candidates[i].Score = score(q, candidates[i])
+	}
+	slices.SortStableFunc(candidates, func(a, b SeriesCandidate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return candidates
+}
+
+// Best returns the first ranked candidate if it scores at least MinScore
+func Best(series string, candidates []SeriesCandidate) (SeriesCandidate, error) {
+	if len(candidates) == 0 || candidates[0].Score < MinScore {
+		return SeriesCandidate{}, yerr.WithStackf(
+			"no match for series <%s> in %d candidates: %w",
+			series,
+			len(candidates),
+			ErrNotFound,
+		)
+	}
+	return candidates[0], nil
+}
+
+// CheckNumericID fails with ErrNotFound unless id is a decimal number, ids
+// from requests end up in api paths so nothing else may get through
+func CheckNumericID(id string) error {
+	if id == "" || strings.Trim(id, "0123456789") != "" {
+		return yerr.WithStackf("invalid id <%s>: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+func score(q match.Query, c SeriesCandidate) float64 {
+	titles := make([]string, len(c.Titles))
+	for i, t := range c.Titles {
+		titles[i] = t.Value
+	}
+
+	return match.Score(q, match.Candidate{
+		Titles: titles,
+		Year:   c.Year,
+		Type:   c.Type,
+	})
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vyxn/yuzu/internal/standard"
+)
+
+func TestBest(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		found  bool
+	}{
+		{"no candidates", nil, false},
+		{"at the minimum", []float64{MinScore, 0.2}, true},
+		{"below the minimum", []float64{MinScore - 0.01}, false},
+		{"perfect", []float64{1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := []SeriesCandidate{}
+			for _, s := range tt.scores {
+				candidates = append(candidates, SeriesCandidate{Score: s})
+			}
+
+			_, err := Best("series", candidates)
+			if tt.found && err != nil {
+				t.Errorf("Best() error = %v", err)
+			}
+			if !tt.found && !errors.Is(err, ErrNotFound) {
+				t.Errorf("Best() error = %v, want not found", err)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	candidates := Rank("Berserk (1989)", []SeriesCandidate{
+		{ProviderID: "novel", Titles: []standard.Title{{Value: "Berserk"}}, Type: "novel"},
+		{ProviderID: "other", Titles: []standard.Title{{Value: "Beserker"}}},
+		{ProviderID: "manga", Titles: []standard.Title{{Value: "Berserk"}}, Year: 1989},
+	})
+
+	got := []string{}
+	for _, c := range candidates {
+		got = append(got, c.ProviderID)
+	}
+	if len(got) != 3 || got[0] != "manga" || got[1] != "novel" {
+		t.Errorf("Rank() order = %v", got)
+	}
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// a newline right after the tag is the same line break
+	reBreak = regexp.MustCompile(`(?i)<br\s*/?>\r?\n?`)
+	reTag   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// StripHTML turns the html some providers use in descriptions into plain
+// text, line breaks are kept
+func StripHTML(s string) string {
+	s = reBreak.ReplaceAllString(s, "\n")
+	s = reTag.ReplaceAllString(s, "")
+	return strings.TrimSpace(html.UnescapeString(s))
+}
+
+// TrimChapter drops the leading zeros of a chapter number as written in file
+// names, e.g. "007" becomes "7", apis only know the plain number
+func TrimChapter(chapter string) string {
+	chapter = strings.TrimSpace(chapter)
+	if chapter == "" {
+		return ""
+	}
+	n := strings.TrimLeft(chapter, "0")
+	if n == "" || n[0] == '.' {
+		n = "0" + n
+	}
+	return n
+}
@@ -0,0 +1,22 @@
+package provider
+
+import "testing"
+
+func TestTrimChapter(t *testing.T) {
+	tests := []struct {
+		chapter, want string
+	}{
+		{"001", "1"},
+		{"1", "1"},
+		{"010.5", "10.5"},
+		{"000", "0"},
+		{"00.5", "0.5"},
+		{" 07 ", "7"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := TrimChapter(tt.chapter); got != tt.want {
+			t.Errorf("TrimChapter(%q) = %q, want %q", tt.chapter, got, tt.want)
+		}
+	}
+}
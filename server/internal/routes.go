@@ -1,35 +1,55 @@
 package internal
 
 import (
+	"bytes"
+	"cmp"
+	"context"
 	_ "embed"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
+	"github.com/vyxn/yuzu/internal/cache"
+	"github.com/vyxn/yuzu/internal/config"
 	"github.com/vyxn/yuzu/internal/kitsu"
 	"github.com/vyxn/yuzu/internal/lib"
+	"github.com/vyxn/yuzu/internal/override"
 	"github.com/vyxn/yuzu/internal/pkg/assert"
+	"github.com/vyxn/yuzu/internal/pkg/req"
 	"github.com/vyxn/yuzu/internal/pkg/yerr"
 	"github.com/vyxn/yuzu/internal/provider"
-	"github.com/vyxn/yuzu/internal/provider/comicvine"
-	"github.com/vyxn/yuzu/internal/provider/myanimelist"
+	_ "github.com/vyxn/yuzu/internal/provider/anilist"
+	_ "github.com/vyxn/yuzu/internal/provider/comicvine"
+	"github.com/vyxn/yuzu/internal/provider/local"
+	_ "github.com/vyxn/yuzu/internal/provider/mangadex"
+	_ "github.com/vyxn/yuzu/internal/provider/mangaupdates"
+	_ "github.com/vyxn/yuzu/internal/provider/metron"
+	_ "github.com/vyxn/yuzu/internal/provider/myanimelist"
+	"github.com/vyxn/yuzu/internal/standard"
 )
 
 //go:embed static/favicon.ico
 var favicon []byte
 
-var providerComicVine provider.ComicInfoProvider
-var providerMyAnimeList provider.ComicInfoProvider
+var providerTimeout time.Duration
 
-func SetupRoutes(e *echo.Echo) {
-	providerComicVine = comicvine.NewComicVineProvider(
-		os.Getenv("COMICVINE_API_KEY"),
-	)
-	providerMyAnimeList = myanimelist.NewMyAnimeListProvider(
-		os.Getenv("MYANIMELIST_CLIENT_ID"),
+var db *sqlx.DB
+
+func SetupRoutes(e *echo.Echo, database *sqlx.DB) {
+	db = database
+	standard.SetTitlePreference(
+		standard.ParseTitlePreference(os.Getenv("TITLE_LANGUAGES")),
 	)
 
+	providerTimeout, _ = time.ParseDuration(os.Getenv("PROVIDER_TIMEOUT"))
+
 	e.GET("/favicon.ico", func(c echo.Context) error {
 		return c.Blob(http.StatusOK, "image/x-icon", favicon)
 	})
@@ -38,6 +58,20 @@ func SetupRoutes(e *echo.Echo) {
 	e.GET("/mangaInfo", hMangaInfo)
 	e.GET("/mangaChapters", hMangaChapters)
 	e.GET("/comicinfo", hComicInfo)
+	e.GET("/compare", hCompare)
+	e.GET("/search", hSearch)
+	e.GET("/overrides", hGetOverrides)
+	e.PUT("/overrides", hPutOverride)
+	e.DELETE("/overrides", hDeleteOverride)
+	e.GET("/providers", hProviders)
+	e.GET("/cache", hCacheStats)
+	e.DELETE("/cache", hInvalidateCache)
+	e.GET("/config/merge", hGetMergePolicy)
+	e.PUT("/config/merge", hPutMergePolicy)
+	e.GET("/config/providers", hGetProvidersConfig)
+	e.PUT("/config/providers", hPutProvidersConfig)
+	e.GET("/config/cache", hGetCacheConfig)
+	e.PUT("/config/cache", hPutCacheConfig)
 	e.GET("/lib", hLib)
 }
 
@@ -52,12 +86,15 @@ func hello(c echo.Context) error {
 }
 
 func hMangaInfo(c echo.Context) error {
-	name := c.QueryParam("name")
-	mangaSearchRes := kitsu.GetSearchByName(name)
-	mangaURL := kitsu.ParseMangaListSelfLink(mangaSearchRes)
+	ctx, err := kitsuContext(c)
+	if err != nil {
+		return err
+	}
 
-	mangaInfoRes := kitsu.GetURL(mangaURL)
-	mangaInfo := kitsu.ParseMangaInfo(mangaInfoRes)
+	mangaInfo, err := kitsuManga(ctx, c.QueryParam("name"))
+	if err != nil {
+		return err
+	}
 
 	return c.String(http.StatusOK, fmt.Sprintf("%+v", mangaInfo))
 }
@@ -66,56 +103,512 @@ func hMangaChapters(c echo.Context) error {
 	name := c.QueryParam("name")
 	chapter := c.QueryParam("chapter")
 	//volume := c.QueryParam("volume")
+	ctx, err := kitsuContext(c)
+	if err != nil {
+		return err
+	}
 
-	mangaSearchRes := kitsu.GetSearchByName(name)
-	mangaURL := kitsu.ParseMangaListSelfLink(mangaSearchRes)
-
-	mangaInfoRes := kitsu.GetURL(mangaURL)
-	mangaInfo := kitsu.ParseMangaInfo(mangaInfoRes)
+	mangaInfo, err := kitsuManga(ctx, name)
+	if err != nil {
+		return err
+	}
 
 	var info []byte
 	if chapter != "" {
-		info = kitsu.GetMangaChapterInfo(mangaInfo.Data.ID, chapter)
+		info, err = kitsu.GetMangaChapterInfo(
+			ctx,
+			kitsu.DefaultClient,
+			mangaInfo.Data.ID,
+			chapter,
+		)
 	} else {
-		info = kitsu.GetURL(mangaInfo.Data.Relationships.Chapters.Links.Self)
+		info, err = kitsu.GetURL(
+			ctx,
+			kitsu.DefaultClient,
+			mangaInfo.Data.Relationships.Chapters.Links.Self,
+		)
+	}
+	if err != nil {
+		return kitsuError(err)
 	}
 
 	return c.String(http.StatusOK, fmt.Sprintf("%+v", string(info)))
 }
 
+// kitsuManga fetches the first kitsu search result for name
+func kitsuManga(ctx context.Context, name string) (kitsu.MangaInfo, error) {
+	mangaSearchRes, err := kitsu.GetSearchByName(ctx, kitsu.DefaultClient, name)
+	if err != nil {
+		return kitsu.MangaInfo{}, kitsuError(err)
+	}
+	mangaURL, err := kitsu.ParseMangaListSelfLink(mangaSearchRes)
+	if err != nil {
+		return kitsu.MangaInfo{}, kitsuError(err)
+	}
+
+	mangaInfoRes, err := kitsu.GetURL(ctx, kitsu.DefaultClient, mangaURL)
+	if err != nil {
+		return kitsu.MangaInfo{}, kitsuError(err)
+	}
+	mangaInfo, err := kitsu.ParseMangaInfo(mangaInfoRes)
+	if err != nil {
+		return kitsu.MangaInfo{}, kitsuError(err)
+	}
+
+	return mangaInfo, nil
+}
+
+// kitsuError answers missing series and chapters with a 404
+func kitsuError(err error) error {
+	if errors.Is(err, provider.ErrNotFound) {
+		return echo.ErrNotFound.SetInternal(err)
+	}
+	return err
+}
+
 func hComicInfo(c echo.Context) error {
 	series := c.QueryParam("s")
 	chapter := c.QueryParam("c")
 	prov := c.QueryParam("p")
+	mode := standard.ParseValidationMode(c.QueryParam("validate"))
+	exporter, err := standard.ExporterByFormat(
+		cmp.Or(c.QueryParam("f"), "comicinfo"),
+	)
+	if err != nil {
+		return echo.ErrBadRequest.SetInternal(err)
+	}
 
-	ps := []provider.ComicInfoProvider{}
-	switch prov {
-	case "comicvine":
-		ps = append(ps, providerComicVine)
-	case "kitsu":
-		ps = append(ps, kitsu.NewKitsuProvider())
-	case "myanimelist":
-		ps = append(ps, providerMyAnimeList)
-	default:
-		ps = append(ps, providerComicVine, providerMyAnimeList)
-		ps = append(ps, kitsu.NewKitsuProvider())
+	id := c.QueryParam("id")
 
+	ps, err := providers(c, prov)
+	if err != nil {
+		return err
+	}
+	ov, err := override.Lookup(c.Request().Context(), db, series, chapter)
+	if err != nil {
+		return err
+	}
+	policy, err := mergePolicy(c)
+	if err != nil {
+		return err
+	}
+	var ci *standard.ComicInfoChapter
+	var provenance provider.Provenance
+	var failed provider.ProviderErrors
+	if id != "" {
+		if len(ps) != 1 {
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				"id needs a single provider in p",
+			)
+		}
+		var res *standard.ComicInfoChapter
+		res, err = ps[0].ProvideChapterByID(c.Request().Context(), id, chapter)
+		if err == nil {
+			ci, provenance = policy.Merge(
+				[]provider.ProviderResult{{Provider: ps[0].Name(), Chapter: res}},
+			)
+			if ov != nil {
+				ov.Apply(ci, provenance)
+			}
+		}
+	} else {
+		ci, provenance, failed, err = provider.MergedComicInfoChapter(
+			c.Request().Context(),
+			provider.MergeOptions{
+				Policy:   policy,
+				Timeout:  providerTimeout,
+				Required: splitParam(c.QueryParam("required")),
+				Override: ov,
+			},
+			series,
+			chapter,
+			ps...)
+		for _, name := range slices.Sorted(maps.Keys(failed)) {
+			c.Response().Header().Add(
+				"X-Provider-Error",
+				name+": "+failed[name].Error(),
+			)
+		}
 	}
-	ci, err := provider.MergedComicInfoChapter(
-		c.Request().Context(),
-		series,
-		chapter,
-		ps...)
 	if err != nil {
 		return echo.ErrNotFound.SetInternal(err)
 	}
 
 	assert.Assert(ci != nil, "we should have a comicinfochapter here")
-	return c.XML(http.StatusOK, ci)
+	if c.QueryParam("notes") == "1" {
+		ci.Notes = strings.TrimSpace(ci.Notes + "\n\n" + provenance.Summary())
+	}
+	if err := ci.Check(mode); err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err).
+			SetInternal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.Export(&buf, *ci); err != nil {
+		return err
+	}
+	if c.QueryParam("provenance") == "1" {
+		return c.JSON(http.StatusOK, map[string]any{
+			"format":     exporter.Format(),
+			"metadata":   buf.String(),
+			"provenance": provenance,
+			"errors":     failed,
+		})
+	}
+	return c.Blob(http.StatusOK, exporter.ContentType(), buf.Bytes())
+}
+
+func hCompare(c echo.Context) error {
+	series := c.QueryParam("s")
+	chapter := c.QueryParam("c")
+
+	policy, err := mergePolicy(c)
+	if err != nil {
+		return err
+	}
+
+	ps, err := providers(c, c.QueryParam("p"))
+	if err != nil {
+		return err
+	}
+
+	results, failed := provider.FanOut(
+		c.Request().Context(),
+		providerTimeout,
+		ps,
+		func(
+			ctx context.Context,
+			p provider.ComicInfoProvider,
+		) (*standard.ComicInfoChapter, error) {
+			return p.ProvideChapter(ctx, series, chapter)
+		},
+	)
+	merged, provenance := policy.Merge(results)
+
+	ov, err := override.Lookup(c.Request().Context(), db, series, chapter)
+	if err != nil {
+		return err
+	}
+	if ov != nil {
+		ov.Apply(merged, provenance)
+		results = append(results, provider.ProviderResult{
+			Provider: provider.OverrideSource,
+			Chapter:  ov.Fields,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"series":  series,
+		"chapter": chapter,
+		"fields":  provider.Compare(results, merged, provenance),
+		"merged":  merged,
+		"errors":  failed,
+	})
+}
+
+// hSearch returns the candidates of every provider that answered, the others
+// are listed in errors so one outage doesn't hide the rest
+func hSearch(c echo.Context) error {
+	series := c.QueryParam("s")
+	prov := c.QueryParam("p")
+
+	ps, err := providers(c, prov)
+	if err != nil {
+		return err
+	}
+
+	out := map[string][]provider.SeriesCandidate{}
+	failed := provider.ProviderErrors{}
+	for _, p := range ps {
+		candidates, err := p.SearchSeries(c.Request().Context(), series)
+		if err != nil {
+			failed[p.Name()] = err
+			continue
+		}
+		out[p.Name()] = candidates
+	}
+	if err := failed.Check(nil, len(out)); err != nil {
+		return echo.ErrNotFound.SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"results": out,
+		"errors":  failed,
+	})
+}
+
+// splitParam splits a comma separated query parameter
+func splitParam(param string) []string {
+	out := []string{}
+	for v := range strings.SplitSeq(param, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// providersConfig returns the provider settings stored in the config table
+func providersConfig(c echo.Context) (provider.ProvidersConfig, error) {
+	cfg := provider.ProvidersConfig{}
+	err := config.Get(
+		c.Request().Context(),
+		db,
+		provider.ProvidersConfigKey,
+		&cfg,
+	)
+	return cfg, err
+}
+
+// providers returns the provider named prov or every available provider in
+// priority order when prov is empty, their responses go through the cache
+func providers(
+	c echo.Context,
+	prov string,
+) ([]provider.ComicInfoProvider, error) {
+	cfg, err := providersConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := cache.Load(c.Request().Context(), db)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := cfg.Providers()
+	if prov != "" {
+		p, err := cfg.Provider(prov)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).
+				SetInternal(err)
+		}
+		ps = []provider.ComicInfoProvider{p}
+	}
+	for i, p := range ps {
+		ps[i] = provider.Cached(p, rc)
+	}
+	return ps, nil
+}
+
+// kitsuContext returns the request context with the kitsu response cache
+func kitsuContext(c echo.Context) (context.Context, error) {
+	rc, err := cache.Load(c.Request().Context(), db)
+	if err != nil {
+		return nil, err
+	}
+	return req.WithCache(c.Request().Context(), rc.For("kitsu")), nil
+}
+
+func hProviders(c echo.Context) error {
+	cfg, err := providersConfig(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, cfg.Statuses())
+}
+
+func hGetProvidersConfig(c echo.Context) error {
+	cfg, err := providersConfig(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, cfg)
+}
+
+func hPutProvidersConfig(c echo.Context) error {
+	var cfg provider.ProvidersConfig
+	if err := c.Bind(&cfg); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).
+			SetInternal(err)
+	}
+
+	err := config.Set(
+		c.Request().Context(),
+		db,
+		provider.ProvidersConfigKey,
+		cfg,
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, cfg.Statuses())
+}
+
+// mergePolicy returns the policy stored in the config table, or the default
+// one when none was stored
+func mergePolicy(c echo.Context) (provider.MergePolicy, error) {
+	policy := provider.DefaultMergePolicy()
+	err := config.Get(
+		c.Request().Context(),
+		db,
+		provider.MergePolicyConfigKey,
+		&policy,
+	)
+	return policy, err
+}
+
+func hGetMergePolicy(c echo.Context) error {
+	policy, err := mergePolicy(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, policy)
+}
+
+func hPutMergePolicy(c echo.Context) error {
+	var policy provider.MergePolicy
+	if err := c.Bind(&policy); err != nil {
+		return err
+	}
+	if err := policy.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).
+			SetInternal(err)
+	}
+
+	err := config.Set(
+		c.Request().Context(),
+		db,
+		provider.MergePolicyConfigKey,
+		policy,
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, policy)
+}
+
+func hCacheStats(c echo.Context) error {
+	rc, err := cache.Load(c.Request().Context(), db)
+	if err != nil {
+		return err
+	}
+
+	stats, err := rc.Stats(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, stats)
+}
+
+// hInvalidateCache drops the cached responses of provider p whose request
+// contains match, both default to everything
+func hInvalidateCache(c echo.Context) error {
+	rc, err := cache.Load(c.Request().Context(), db)
+	if err != nil {
+		return err
+	}
+
+	n, err := rc.Invalidate(
+		c.Request().Context(),
+		c.QueryParam("p"),
+		c.QueryParam("match"),
+	)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]int64{"deleted": n})
+}
+
+func hGetCacheConfig(c echo.Context) error {
+	cfg, err := cache.LoadConfig(c.Request().Context(), db)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, cfg)
+}
+
+func hPutCacheConfig(c echo.Context) error {
+	var cfg cache.Config
+	if err := c.Bind(&cfg); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).
+			SetInternal(err)
+	}
+
+	err := config.Set(c.Request().Context(), db, cache.ConfigKey, cfg)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, cfg)
+}
+
+func hGetOverrides(c echo.Context) error {
+	series := c.QueryParam("s")
+	if !c.QueryParams().Has("c") {
+		overrides, err := override.List(c.Request().Context(), db, series)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, overrides)
+	}
+
+	o, err := override.Get(c.Request().Context(), db, series, c.QueryParam("c"))
+	if err != nil {
+		return err
+	}
+	if o == nil {
+		return echo.ErrNotFound
+	}
+	return c.JSON(http.StatusOK, o)
+}
+
+func hPutOverride(c echo.Context) error {
+	var o override.Override
+	if err := c.Bind(&o); err != nil {
+		return err
+	}
+	if err := o.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).
+			SetInternal(err)
+	}
+
+	if err := override.Put(c.Request().Context(), db, o); err != nil {
+		return err
+	}
+
+	stored, err := override.Get(c.Request().Context(), db, o.Series, o.Chapter)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, stored)
+}
+
+func hDeleteOverride(c echo.Context) error {
+	deleted, err := override.Delete(
+		c.Request().Context(),
+		db,
+		c.QueryParam("s"),
+		c.QueryParam("c"),
+	)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return echo.ErrNotFound
+	}
+	return c.NoContent(http.StatusNoContent)
 }
 
 func hLib(c echo.Context) error {
-	err := lib.Process("testlib")
+	exporters, err := standard.ParseExporters(c.QueryParam("f"))
+	if err != nil {
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = lib.Process(db, local.Root(), exporters...)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,99 @@
+package standard
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CoMet http://www.denvog.com/comet/comet-specification/
+type CoMet struct {
+	XMLName          xml.Name `xml:"comet"`
+	XMLNS            string   `xml:"xmlns,attr"`
+	XSI              string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation   string   `xml:"xsi:schemaLocation,attr"`
+	Title            string   `xml:"title"`
+	Description      string   `xml:"description,omitempty"`
+	Series           string   `xml:"series,omitempty"`
+	Issue            string   `xml:"issue,omitempty"`
+	Volume           int      `xml:"volume,omitempty"`
+	Publisher        string   `xml:"publisher,omitempty"`
+	Date             string   `xml:"date,omitempty"`
+	Genre            []string `xml:"genre,omitempty"`
+	Character        []string `xml:"character,omitempty"`
+	Format           string   `xml:"format,omitempty"`
+	Language         string   `xml:"language,omitempty"`
+	Rating           string   `xml:"rating,omitempty"`
+	Identifier       string   `xml:"identifier,omitempty"`
+	Pages            int      `xml:"pages,omitempty"`
+	Writer           []string `xml:"writer,omitempty"`
+	Penciller        []string `xml:"penciller,omitempty"`
+	Editor           []string `xml:"editor,omitempty"`
+	CoverDesigner    []string `xml:"coverDesigner,omitempty"`
+	Letterer         []string `xml:"letterer,omitempty"`
+	Inker            []string `xml:"inker,omitempty"`
+	Colorist         []string `xml:"colorist,omitempty"`
+	ReadingDirection string   `xml:"readingDirection,omitempty"`
+}
+
+// NewCoMet converts ci to the CoMet model
+func NewCoMet(ci ComicInfoChapter) CoMet {
+	c := CoMet{
+		XMLNS:          "http://www.denvog.com/comet/",
+		XSI:            "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "http://www.denvog.com/comet/comet.xsd",
+		Title:          ci.Title,
+		Description:    ci.Summary,
+		Series:         ci.Series,
+		Issue:          ci.Number,
+		Volume:         ci.Volume,
+		Publisher:      ci.Publisher,
+		Genre:          ci.Genre,
+		Character:      ci.Characters,
+		Format:         ci.Format,
+		Language:       ci.LanguageISO,
+		Rating:         ci.AgeRating,
+		Identifier:     ci.GTIN,
+		Pages:          ci.PageCount,
+		Writer:         ci.Writer,
+		Penciller:      ci.Penciller,
+		Editor:         ci.Editor,
+		CoverDesigner:  ci.CoverArtist,
+		Letterer:       ci.Letterer,
+		Inker:          ci.Inker,
+		Colorist:       ci.Colorist,
+	}
+
+	// title is the only required element
+	if c.Title == "" {
+		c.Title = strings.TrimSpace(ci.Series + " " + ci.Number)
+	}
+
+	if ci.Year != 0 {
+		c.Date = fmt.Sprintf("%04d", ci.Year)
+		if ci.Month != 0 {
+			c.Date += fmt.Sprintf("-%02d", ci.Month)
+		}
+	}
+
+	switch ci.Manga {
+	case "YesAndRightToLeft":
+		c.ReadingDirection = "rtl"
+	case "Yes", "No":
+		c.ReadingDirection = "ltr"
+	}
+
+	return c
+}
+
+// CoMetExporter writes the CoMet xml format
+type CoMetExporter struct{}
+
+func (CoMetExporter) Format() string      { return "comet" }
+func (CoMetExporter) Filename() string    { return "CoMet.xml" }
+func (CoMetExporter) ContentType() string { return "application/xml" }
+
+func (CoMetExporter) Export(w io.Writer, ci ComicInfoChapter) error {
+	return encodeXML(w, NewCoMet(ci))
+}
@@ -0,0 +1,97 @@
+package standard
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"time"
+)
+
+// ComicBookInfo https://code.google.com/archive/p/comicbookinfo/wikis/Example.wiki
+type ComicBookInfo struct {
+	AppID        string              `json:"appID"`
+	LastModified string              `json:"lastModified"`
+	Info         ComicBookInfoFields `json:"ComicBookInfo/1.0"`
+}
+
+type ComicBookInfoFields struct {
+	Series           string                `json:"series,omitempty"`
+	Title            string                `json:"title,omitempty"`
+	Publisher        string                `json:"publisher,omitempty"`
+	PublicationMonth int                   `json:"publicationMonth,omitempty"`
+	PublicationYear  int                   `json:"publicationYear,omitempty"`
+	Issue            string                `json:"issue,omitempty"`
+	NumberOfIssues   int                   `json:"numberOfIssues,omitempty"`
+	Volume           int                   `json:"volume,omitempty"`
+	Rating           int                   `json:"rating,omitempty"`
+	Genre            string                `json:"genre,omitempty"`
+	Language         string                `json:"language,omitempty"`
+	Credits          []ComicBookInfoCredit `json:"credits,omitempty"`
+	Tags             []string              `json:"tags,omitempty"`
+	Comments         string                `json:"comments,omitempty"`
+}
+
+type ComicBookInfoCredit struct {
+	Person  string `json:"person"`
+	Role    string `json:"role"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// NewComicBookInfo converts ci to the ComicBookInfo model
+func NewComicBookInfo(ci ComicInfoChapter) ComicBookInfo {
+	info := ComicBookInfoFields{
+		Series:           ci.Series,
+		Title:            ci.Title,
+		Publisher:        ci.Publisher,
+		PublicationMonth: ci.Month,
+		PublicationYear:  ci.Year,
+		Issue:            ci.Number,
+		NumberOfIssues:   ci.Count,
+		Volume:           ci.Volume,
+		Rating:           int(math.Round(ci.CommunityRating)),
+		Genre:            ci.Genre.String(),
+		Language:         ci.LanguageISO,
+		Tags:             ci.Tags,
+		Comments:         ci.Summary,
+	}
+
+	for _, c := range credits(ci) {
+		for i, r := range c.Roles {
+			info.Credits = append(info.Credits, ComicBookInfoCredit{
+				Person:  c.Person,
+				Role:    r,
+				Primary: i == 0 && r == "Writer",
+			})
+		}
+	}
+
+	return ComicBookInfo{
+		AppID:        "yuzu",
+		LastModified: time.Now().UTC().Format(time.DateTime),
+		Info:         info,
+	}
+}
+
+// EqualComicBookInfo reports whether the encoded ComicBookInfo a and b hold
+// the same metadata, lastModified is ignored
+func EqualComicBookInfo(a, b string) bool {
+	var ia, ib ComicBookInfo
+	if json.Unmarshal([]byte(a), &ia) != nil ||
+		json.Unmarshal([]byte(b), &ib) != nil {
+		return a == b
+	}
+	return ia.AppID == ib.AppID && reflect.DeepEqual(ia.Info, ib.Info)
+}
+
+// ComicBookInfoExporter writes ComicBookInfo json, readers expect it in the
+// archive comment
+type ComicBookInfoExporter struct{}
+
+func (ComicBookInfoExporter) Format() string      { return "comicbookinfo" }
+func (ComicBookInfoExporter) Filename() string    { return "" }
+func (ComicBookInfoExporter) ContentType() string { return "application/json" }
+
+func (ComicBookInfoExporter) Export(w io.Writer, ci ComicInfoChapter) error {
+	return json.NewEncoder(w).Encode(NewComicBookInfo(ci))
+}
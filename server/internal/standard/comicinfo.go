@@ -8,51 +8,116 @@ import (
 
 // ComicInfoChapter https://anansi-project.github.io
 type ComicInfoChapter struct {
-	XMLName         xml.Name `xml:"ComicInfo"`
-	Title           string   `xml:"Title,omitempty"`
-	Series          string   `xml:"Series,omitempty"`
-	Number          string   `xml:"Number,omitempty"`
-	Count           int      `xml:"Count,omitempty"`
-	Volume          int      `xml:"Volume,omitempty"`
-	AlternateSeries string   `xml:"AlternateSeries,omitempty"`
-	AlternateNumber string   `xml:"AlternateNumber,omitempty"`
-	AlternateCount  int      `xml:"AlternateCount,omitempty"`
-	Summary         string   `xml:"Summary,omitempty"`
-	Notes           string   `xml:"Notes,omitempty"`
-	Year            int      `xml:"Year,omitempty"`
-	Month           int      `xml:"Month,omitempty"`
-	Day             int      `xml:"Day,omitempty"`
-	Writer          string   `xml:"Writer,omitempty"`
-	Penciller       string   `xml:"Penciller,omitempty"`
-	Inker           string   `xml:"Inker,omitempty"`
-	Colorist        string   `xml:"Colorist,omitempty"`
-	Letterer        string   `xml:"Letterer,omitempty"`
-	CoverArtist     string   `xml:"CoverArtist,omitempty"`
-	Editor          string   `xml:"Editor,omitempty"`
-	Translator      string   `xml:"Translator,omitempty"`
-	Publisher       string   `xml:"Publisher,omitempty"`
-	Imprint         string   `xml:"Imprint,omitempty"`
-	Genre           string   `xml:"Genre,omitempty"`
-	Tags            string   `xml:"Tags,omitempty"`
-	Web             string   `xml:"Web,omitempty"`
-	PageCount       int      `xml:"PageCount,omitempty"`
-	LanguageISO     string   `xml:"LanguageISO,omitempty"`
-	Format          string   `xml:"Format,omitempty"`
-	BlackAndWhite   string   `xml:"BlackAndWhite,omitempty"`
-	Manga           string   `xml:"Manga,omitempty"`
-	Characters      string   `xml:"Characters,omitempty"`
-	Teams           string   `xml:"Teams,omitempty"`
-	Locations       string   `xml:"Locations,omitempty"`
-	ScanInformation string   `xml:"ScanInformation,omitempty"`
-	StoryArc        string   `xml:"StoryArc,omitempty"`
-	StoryArcNumber  string   `xml:"StoryArcNumber,omitempty"`
-	SeriesGroup     string   `xml:"SeriesGroup,omitempty"`
-	AgeRating       string   `xml:"AgeRating,omitempty"`
-	// Pages           []struct `xml:"Pages,omitempty"`
-	CommunityRating     float64 `xml:"CommunityRating,omitempty"`
-	MainCharacterOrTeam string  `xml:"MainCharacterOrTeam,omitempty"`
-	Review              string  `xml:"Review,omitempty"`
-	GTIN                string  `xml:"GTIN,omitempty"`
+	XMLName             xml.Name   `xml:"ComicInfo"`
+	Title               string     `xml:"Title,omitempty"`
+	Series              string     `xml:"Series,omitempty"`
+	Number              string     `xml:"Number,omitempty"`
+	Count               int        `xml:"Count,omitempty"`
+	Volume              int        `xml:"Volume,omitempty"`
+	AlternateSeries     string     `xml:"AlternateSeries,omitempty"`
+	AlternateNumber     string     `xml:"AlternateNumber,omitempty"`
+	AlternateCount      int        `xml:"AlternateCount,omitempty"`
+	Summary             string     `xml:"Summary,omitempty"`
+	Notes               string     `xml:"Notes,omitempty"`
+	Year                int        `xml:"Year,omitempty"`
+	Month               int        `xml:"Month,omitempty"`
+	Day                 int        `xml:"Day,omitempty"`
+	Writer              List       `xml:"Writer,omitempty"`
+	Penciller           List       `xml:"Penciller,omitempty"`
+	Inker               List       `xml:"Inker,omitempty"`
+	Colorist            List       `xml:"Colorist,omitempty"`
+	Letterer            List       `xml:"Letterer,omitempty"`
+	CoverArtist         List       `xml:"CoverArtist,omitempty"`
+	Editor              List       `xml:"Editor,omitempty"`
+	Translator          List       `xml:"Translator,omitempty"`
+	Publisher           string     `xml:"Publisher,omitempty"`
+	Imprint             string     `xml:"Imprint,omitempty"`
+	Genre               List       `xml:"Genre,omitempty"`
+	Tags                List       `xml:"Tags,omitempty"`
+	Web                 string     `xml:"Web,omitempty"`
+	PageCount           int        `xml:"PageCount,omitempty"`
+	LanguageISO         string     `xml:"LanguageISO,omitempty"`
+	Format              string     `xml:"Format,omitempty"`
+	BlackAndWhite       string     `xml:"BlackAndWhite,omitempty"`
+	Manga               string     `xml:"Manga,omitempty"`
+	Characters          List       `xml:"Characters,omitempty"`
+	Teams               List       `xml:"Teams,omitempty"`
+	Locations           List       `xml:"Locations,omitempty"`
+	ScanInformation     string     `xml:"ScanInformation,omitempty"`
+	StoryArc            string     `xml:"StoryArc,omitempty"`
+	StoryArcNumber      string     `xml:"StoryArcNumber,omitempty"`
+	SeriesGroup         string     `xml:"SeriesGroup,omitempty"`
+	AgeRating           string     `xml:"AgeRating,omitempty"`
+	Pages               ComicPages `xml:"Pages,omitempty"`
+	CommunityRating     float64    `xml:"CommunityRating,omitempty"`
+	MainCharacterOrTeam string     `xml:"MainCharacterOrTeam,omitempty"`
+	Review              string     `xml:"Review,omitempty"`
+	GTIN                string     `xml:"GTIN,omitempty"`
+
+	// Attrs and Extra keep what the model doesn't know about so decoding and
+	// encoding an existing ComicInfo.xml doesn't drop vendor extensions
+	Attrs []xml.Attr       `xml:",any,attr"`
+	Extra []UnknownElement `xml:",any"`
+
+	// ProviderID is the ID of the entity the metadata came from at its
+	// provider, it isn't part of ComicInfo.xml
+	ProviderID string `xml:"-"`
+}
+
+// UnknownElement is an element outside of the ComicInfo schema, kept verbatim
+type UnknownElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	InnerXML string     `xml:",innerxml"`
+}
+
+// ComicPageType values from the ComicInfo schema
+const (
+	PageTypeFrontCover    = "FrontCover"
+	PageTypeInnerCover    = "InnerCover"
+	PageTypeRoundup       = "Roundup"
+	PageTypeStory         = "Story"
+	PageTypeAdvertisement = "Advertisement"
+	PageTypeEditorial     = "Editorial"
+	PageTypeLetters       = "Letters"
+	PageTypePreview       = "Preview"
+	PageTypeBackCover     = "BackCover"
+	PageTypeOther         = "Other"
+	PageTypeDeleted       = "Deleted"
+)
+
+// ComicPages is the list of images inside the archive, encoding/xml writes an
+// empty parent element for "Pages>Page,omitempty" so it's marshaled by hand
+type ComicPages []ComicPageInfo
+
+func (p ComicPages) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	s := struct {
+		Page []ComicPageInfo `xml:"Page"`
+	}{p}
+	return e.EncodeElement(s, start)
+}
+
+func (p *ComicPages) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s struct {
+		Page []ComicPageInfo `xml:"Page"`
+	}
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	*p = s.Page
+	return nil
+}
+
+// ComicPageInfo describes a single image inside the archive
+type ComicPageInfo struct {
+	Image       int    `xml:"Image,attr"`
+	Type        string `xml:"Type,attr,omitempty"`
+	DoublePage  bool   `xml:"DoublePage,attr,omitempty"`
+	ImageSize   int64  `xml:"ImageSize,attr,omitempty"`
+	Key         string `xml:"Key,attr,omitempty"`
+	Bookmark    string `xml:"Bookmark,attr,omitempty"`
+	ImageWidth  int    `xml:"ImageWidth,attr,omitempty"`
+	ImageHeight int    `xml:"ImageHeight,attr,omitempty"`
 }
 
 func (c ComicInfoChapter) Encode(w io.Writer) error {
@@ -65,3 +130,13 @@ func (c ComicInfoChapter) Encode(w io.Writer) error {
 
 	return e.Flush()
 }
+
+// EncodeChecked applies mode to c before writing it, in strict mode nothing is
+// written when c doesn't satisfy the schema
+func (c ComicInfoChapter) EncodeChecked(w io.Writer, mode ValidationMode) error {
+	if err := c.Check(mode); err != nil {
+		return err
+	}
+
+	return c.Encode(w)
+}
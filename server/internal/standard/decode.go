@@ -0,0 +1,95 @@
+package standard
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+)
+
+// ComicInfoFilename is the name of the metadata entry inside an archive
+const ComicInfoFilename = "ComicInfo.xml"
+
+// DecodeComicInfo reads a ComicInfo.xml document, elements and attributes the
+// model doesn't know about end up in Extra and Attrs
+func DecodeComicInfo(r io.Reader) (*ComicInfoChapter, error) {
+	ci := &ComicInfoChapter{}
+	if err := xml.NewDecoder(r).Decode(ci); err != nil {
+		return nil, yerr.WithStackf("decoding comicinfo: %w", err)
+	}
+
+	// namespace declarations are written again by the encoder from the
+	// element names, keeping them here would duplicate them on the next encode
+	ci.Attrs = dropNamespaces(ci.Attrs)
+	for i := range ci.Extra {
+		ci.Extra[i].Attrs = dropNamespaces(ci.Extra[i].Attrs)
+	}
+
+	return ci, nil
+}
+
+// dropNamespaces returns attrs without namespace declarations, nil when
+// nothing is left
+func dropNamespaces(attrs []xml.Attr) []xml.Attr {
+	out := attrs[:0]
+	for _, a := range attrs {
+		if a.Name.Space == "xmlns" || a.Name.Local == "xmlns" {
+			continue
+		}
+		out = append(out, a)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// ReadComicInfoFile decodes a ComicInfo.xml stored on disk
+func ReadComicInfoFile(file string) (*ComicInfoChapter, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, yerr.WithStackf("opening <%s>: %w", file, err)
+	}
+	defer f.Close()
+
+	return DecodeComicInfo(f)
+}
+
+// ReadComicInfoCBZ decodes the ComicInfo.xml entry of a cbz archive, the
+// returned error wraps os.ErrNotExist when the archive has none
+func ReadComicInfoCBZ(file string) (*ComicInfoChapter, error) {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, yerr.WithStackf("opening archive <%s>: %w", file, err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if !strings.EqualFold(path.Base(f.Name), ComicInfoFilename) {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, yerr.WithStackf(
+				"opening archive entry <%s>: %w",
+				f.Name,
+				err,
+			)
+		}
+		defer rc.Close()
+
+		return DecodeComicInfo(rc)
+	}
+
+	return nil, yerr.WithStackf(
+		"no %s in <%s>: %w",
+		ComicInfoFilename,
+		file,
+		os.ErrNotExist,
+	)
+}
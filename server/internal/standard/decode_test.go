@@ -0,0 +1,121 @@
+package standard
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const existingComicInfo = `<?xml version="1.0" encoding="utf-8"?>
+<ComicInfo xmlns:xsd="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" vendor:app="reader" xmlns:vendor="urn:reader">
+  <Title>Chapter One</Title>
+  <Series>Berserk</Series>
+  <Number>1</Number>
+  <Genre>Action, Dark Fantasy</Genre>
+  <ReaderProgress page="12">read</ReaderProgress>
+  <vendor:Rating stars="5"><Value>5</Value></vendor:Rating>
+</ComicInfo>`
+
+func TestDecodeComicInfoRoundTrip(t *testing.T) {
+	ci, err := DecodeComicInfo(strings.NewReader(existingComicInfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ci.Title != "Chapter One" || ci.Series != "Berserk" ||
+		!reflect.DeepEqual(ci.Genre, List{"Action", "Dark Fantasy"}) {
+		t.Errorf("DecodeComicInfo() = %+v", ci)
+	}
+	if len(ci.Attrs) != 1 || ci.Attrs[0].Name.Local != "app" {
+		t.Errorf("Attrs = %+v, want only the vendor attribute", ci.Attrs)
+	}
+	if len(ci.Extra) != 2 {
+		t.Fatalf("Extra = %+v, want both unknown elements", ci.Extra)
+	}
+
+	var buf bytes.Buffer
+	if err := ci.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`page="12"`,
+		"read</ReaderProgress>",
+		`stars="5"><Value>5</Value>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("encoded comicinfo lacks %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, `xmlns="urn:reader"`); n != 1 {
+		t.Errorf("unknown element namespace written %d times:\n%s", n, out)
+	}
+
+	again, err := DecodeComicInfo(&buf)
+	if err != nil {
+		t.Fatalf("decoding the encoded comicinfo: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(again.Extra, ci.Extra) {
+		t.Errorf(
+			"Extra after a round trip =\n%+v\nwant\n%+v",
+			again.Extra,
+			ci.Extra,
+		)
+	}
+	if again.Title != ci.Title || !reflect.DeepEqual(again.Genre, ci.Genre) ||
+		!reflect.DeepEqual(again.Attrs, ci.Attrs) {
+		t.Errorf("fields after a round trip = %+v", again)
+	}
+}
+
+func TestDecodeComicInfoMalformed(t *testing.T) {
+	_, err := DecodeComicInfo(strings.NewReader("<ComicInfo><Title>"))
+	if err == nil {
+		t.Error("DecodeComicInfo() of a truncated document = nil error")
+	}
+}
+
+func TestReadComicInfoCBZ(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, entries map[string]string) string {
+		file := filepath.Join(dir, name)
+		f, err := os.Create(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		w := zip.NewWriter(f)
+		for name, data := range entries {
+			e, err := w.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.Write([]byte(data))
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	with := write("with.cbz", map[string]string{
+		"001.jpg":           "",
+		"sub/comicinfo.xml": existingComicInfo,
+	})
+	ci, err := ReadComicInfoCBZ(with)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ci.Series != "Berserk" {
+		t.Errorf("ReadComicInfoCBZ() = %+v", ci)
+	}
+
+	without := write("without.cbz", map[string]string{"001.jpg": ""})
+	if _, err := ReadComicInfoCBZ(without); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadComicInfoCBZ() without comicinfo error = %v", err)
+	}
+}
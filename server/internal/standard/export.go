@@ -0,0 +1,171 @@
+package standard
+
+import (
+	"encoding/xml"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+)
+
+// Exporter writes a ComicInfoChapter in a specific metadata format
+type Exporter interface {
+	// Format is the short name used to select the exporter, e.g. "comicinfo"
+	Format() string
+	// Filename is the name of the file the format is stored in, an empty
+	// string means it is stored in the archive comment instead
+	Filename() string
+	ContentType() string
+	Export(w io.Writer, ci ComicInfoChapter) error
+}
+
+var exporters = []Exporter{
+	ComicInfoExporter{},
+	MetronInfoExporter{},
+	CoMetExporter{},
+	ComicBookInfoExporter{},
+}
+
+// Exporters returns every known exporter, ComicInfo first
+func Exporters() []Exporter {
+	return slices.Clone(exporters)
+}
+
+// ExporterByFormat returns the exporter registered under format
+func ExporterByFormat(format string) (Exporter, error) {
+	for _, e := range exporters {
+		if strings.EqualFold(e.Format(), format) {
+			return e, nil
+		}
+	}
+	return nil, yerr.WithStackf("unknown export format <%s>", format)
+}
+
+// ParseExporters maps a comma separated list of formats to exporters, an
+// empty list selects ComicInfo only
+func ParseExporters(formats string) ([]Exporter, error) {
+	out := []Exporter{}
+	for f := range strings.SplitSeq(formats, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		e, err := ExporterByFormat(f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	if len(out) == 0 {
+		out = append(out, ComicInfoExporter{})
+	}
+	return out, nil
+}
+
+// ComicInfoExporter writes ComicRack's ComicInfo.xml
+type ComicInfoExporter struct{}
+
+func (ComicInfoExporter) Format() string      { return "comicinfo" }
+func (ComicInfoExporter) Filename() string    { return ComicInfoFilename }
+func (ComicInfoExporter) ContentType() string { return "application/xml" }
+
+func (ComicInfoExporter) Export(w io.Writer, ci ComicInfoChapter) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return ci.Encode(w)
+}
+
+func encodeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(v); err != nil {
+		return err
+	}
+
+	return e.Flush()
+}
+
+// xmlList writes one Child element per item inside the element of the field
+// it is assigned to and nothing at all when empty, encoding/xml writes an
+// empty parent for "Parent>Child,omitempty"
+type xmlList[T any] struct {
+	Child string
+	Items []T
+}
+
+func newXMLList[T any](child string, items []T) xmlList[T] {
+	return xmlList[T]{Child: child, Items: items}
+}
+
+func (l xmlList[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(l.Items) == 0 {
+		return nil
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	child := xml.StartElement{Name: xml.Name{Local: l.Child}}
+	for _, item := range l.Items {
+		if err := e.EncodeElement(item, child); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// splitList splits a comma separated ComicInfo field into its values
+func splitList(s string) []string {
+	out := []string{}
+	for v := range strings.SplitSeq(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// credit is a person with every role they have in a chapter
+type credit struct {
+	Person string
+	Roles  []string
+}
+
+// credits collects the creator fields of ci in a stable order, merging the
+// roles of people listed under several fields
+func credits(ci ComicInfoChapter) []credit {
+	fields := []struct {
+		role   string
+		people List
+	}{
+		{"Writer", ci.Writer},
+		{"Penciller", ci.Penciller},
+		{"Inker", ci.Inker},
+		{"Colorist", ci.Colorist},
+		{"Letterer", ci.Letterer},
+		{"CoverArtist", ci.CoverArtist},
+		{"Editor", ci.Editor},
+		{"Translator", ci.Translator},
+	}
+
+	out := []credit{}
+	idx := map[string]int{}
+	for _, f := range fields {
+		for _, p := range f.people {
+			i, ok := idx[p]
+			if !ok {
+				i = len(out)
+				idx[p] = i
+				out = append(out, credit{Person: p})
+			}
+			out[i].Roles = append(out[i].Roles, f.role)
+		}
+	}
+	return out
+}
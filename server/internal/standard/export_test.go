@@ -0,0 +1,205 @@
+package standard
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var exportChapter = ComicInfoChapter{
+	Title:          "The Brand",
+	Series:         "Berserk",
+	Number:         "1",
+	Count:          374,
+	Volume:         1,
+	Summary:        "Guts arrives.",
+	Year:           1989,
+	Month:          8,
+	Writer:         List{"Miura Kentarou"},
+	Penciller:      List{"Miura Kentarou"},
+	Editor:         List{"Sawamura"},
+	Publisher:      "Hakusensha",
+	Imprint:        "Young Animal",
+	Genre:          List{"Action", "Dark Fantasy"},
+	Characters:     List{"Guts", "Puck"},
+	StoryArc:       "Black Swordsman, Prologue",
+	StoryArcNumber: "1, 2",
+	Web:            "https://example.com/berserk/1 https://example.com/alt",
+	PageCount:      2,
+	LanguageISO:    "en",
+	Format:         "one-shot",
+	Manga:          "YesAndRightToLeft",
+	AgeRating:      "Mature 17+",
+	GTIN:           "9781593070205",
+	Pages:          ComicPages{{Image: 0, Type: PageTypeFrontCover}, {Image: 1}},
+}
+
+func TestExporters(t *testing.T) {
+	tests := []struct {
+		exporter Exporter
+		want     []string
+		absent   []string
+	}{
+		{
+			ComicInfoExporter{},
+			[]string{
+				`<?xml version="1.0"`,
+				"<Series>Berserk</Series>",
+				"<Writer>Miura Kentarou</Writer>",
+				"<Genre>Action, Dark Fantasy</Genre>",
+				`<Page Image="0" Type="FrontCover"></Page>`,
+			},
+			[]string{"ProviderID"},
+		},
+		{
+			MetronInfoExporter{},
+			[]string{
+				`<Name>Berserk</Name>`,
+				`<Publisher><Name>Hakusensha</Name><Imprint>Young Animal</Imprint></Publisher>`,
+				`<Format>One-Shot</Format>`,
+				`<CoverDate>1989-08-01</CoverDate>`,
+				`<Stories><Story>The Brand</Story></Stories>`,
+				`<Arc><Name>Prologue</Name><Number>2</Number></Arc>`,
+				`<GTIN><ISBN>9781593070205</ISBN></GTIN>`,
+				`<URL primary="true">https://example.com/berserk/1</URL>`,
+				`<Creator>Miura Kentarou</Creator><Roles><Role>Writer</Role><Role>Penciller</Role></Roles>`,
+				`<AgeRating>Mature</AgeRating>`,
+			},
+			[]string{"<Teams>", "<Locations>"},
+		},
+		{
+			CoMetExporter{},
+			[]string{
+				`<title>The Brand</title>`,
+				`<date>1989-08</date>`,
+				`<genre>Dark Fantasy</genre>`,
+				`<character>Puck</character>`,
+				`<identifier>9781593070205</identifier>`,
+				`<readingDirection>rtl</readingDirection>`,
+			},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.exporter.Format(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.exporter.Export(&buf, exportChapter); err != nil {
+				t.Fatal(err)
+			}
+			// indentation doesn't matter here
+			out := strings.NewReplacer("\n", "", "  ", "").Replace(buf.String())
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output lacks %s:\n%s", want, buf.String())
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(out, absent) {
+					t.Errorf("output holds %s:\n%s", absent, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCoMetTitleFallback(t *testing.T) {
+	c := NewCoMet(ComicInfoChapter{Series: "Berserk", Number: "1"})
+	if c.Title != "Berserk 1" {
+		t.Errorf("Title = %q, want the series and number", c.Title)
+	}
+}
+
+func TestComicBookInfoExporter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (ComicBookInfoExporter{}).Export(&buf, exportChapter); err != nil {
+		t.Fatal(err)
+	}
+
+	var got ComicBookInfo
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("decoding %s: %v", buf.String(), err)
+	}
+	want := ComicBookInfoFields{
+		Series:           "Berserk",
+		Title:            "The Brand",
+		Publisher:        "Hakusensha",
+		PublicationMonth: 8,
+		PublicationYear:  1989,
+		Issue:            "1",
+		NumberOfIssues:   374,
+		Volume:           1,
+		Genre:            "Action, Dark Fantasy",
+		Language:         "en",
+		Credits: []ComicBookInfoCredit{
+			{Person: "Miura Kentarou", Role: "Writer", Primary: true},
+			{Person: "Miura Kentarou", Role: "Penciller"},
+			{Person: "Sawamura", Role: "Editor"},
+		},
+		Comments: "Guts arrives.",
+	}
+	if got.AppID != "yuzu" || !reflect.DeepEqual(got.Info, want) {
+		t.Errorf("ComicBookInfo =\n%+v\nwant\n%+v", got.Info, want)
+	}
+}
+
+func TestEqualComicBookInfo(t *testing.T) {
+	a := `{"appID":"yuzu","lastModified":"2024-01-01 00:00:00","ComicBookInfo/1.0":{"series":"Berserk"}}`
+	tests := []struct {
+		name string
+		b    string
+		want bool
+	}{
+		{
+			"only lastModified differs",
+			`{"appID":"yuzu","lastModified":"2025-01-01 00:00:00","ComicBookInfo/1.0":{"series":"Berserk"}}`,
+			true,
+		},
+		{
+			"metadata differs",
+			`{"appID":"yuzu","lastModified":"2024-01-01 00:00:00","ComicBookInfo/1.0":{"series":"Vagabond"}}`,
+			false,
+		},
+		{"not json", "a comment", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualComicBookInfo(a, tt.b); got != tt.want {
+				t.Errorf("EqualComicBookInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExporters(t *testing.T) {
+	tests := []struct {
+		formats string
+		want    []string
+		valid   bool
+	}{
+		{"", []string{"comicinfo"}, true},
+		{"metroninfo, CoMet", []string{"metroninfo", "comet"}, true},
+		{"comicbookinfo", []string{"comicbookinfo"}, true},
+		{"comicinfo,acbf", nil, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseExporters(tt.formats)
+		if !tt.valid {
+			if err == nil {
+				t.Errorf("ParseExporters(%q) = nil error", tt.formats)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		formats := []string{}
+		for _, e := range got {
+			formats = append(formats, e.Format())
+		}
+		if !reflect.DeepEqual(formats, tt.want) {
+			t.Errorf("ParseExporters(%q) = %q, want %q", tt.formats, formats, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package standard
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// List is a multi-valued ComicInfo field such as Writer or Genre, it is
+// serialized as a single comma separated element
+type List []string
+
+var titleCaser = cases.Title(language.Und)
+
+// NewList builds a List from values, see List.Add
+func NewList(values ...string) List {
+	return List{}.Add(values...)
+}
+
+// ParseList splits a comma separated field into a List
+func ParseList(s string) List {
+	return NewList(strings.Split(s, ",")...)
+}
+
+// Add appends values not already in l, values are trimmed, values written all
+// in lowercase are title cased and duplicates are compared case-insensitively
+func (l List) Add(values ...string) List {
+	for _, v := range values {
+		v = strings.Join(strings.Fields(v), " ")
+		if v == "" || l.Contains(v) {
+			continue
+		}
+		if strings.ToLower(v) == v {
+			v = titleCaser.String(v)
+		}
+		l = append(l, v)
+	}
+	return l
+}
+
+// Contains reports whether v is in l ignoring case
+func (l List) Contains(v string) bool {
+	for _, e := range l {
+		if strings.EqualFold(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Union returns the values of l followed by the values of o not in l
+func (l List) Union(o List) List {
+	return append(List{}, l...).Add(o...)
+}
+
+func (l List) String() string {
+	return strings.Join(l, ", ")
+}
+
+func (l List) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(l.String(), start)
+}
+
+func (l *List) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	*l = ParseList(s)
+	return nil
+}
@@ -0,0 +1,88 @@
+package standard
+
+import (
+	"encoding/xml"
+	"slices"
+	"testing"
+)
+
+func TestListAdd(t *testing.T) {
+	tests := []struct {
+		name   string
+		list   List
+		values []string
+		want   List
+	}{
+		{
+			"lowercase is title cased",
+			nil,
+			[]string{"slice of life", "sci-fi"},
+			List{"Slice Of Life", "Sci-Fi"},
+		},
+		{
+			"other casing is kept",
+			nil,
+			[]string{"BL", "iPhone", "McDonald"},
+			List{"BL", "iPhone", "McDonald"},
+		},
+		{
+			"whitespace is collapsed",
+			nil,
+			[]string{"  Dark \t Fantasy ", "", "   "},
+			List{"Dark Fantasy"},
+		},
+		{
+			"duplicates ignore case",
+			List{"Action"},
+			[]string{"action", "ACTION", "Drama", "drama"},
+			List{"Action", "Drama"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.list.Add(tt.values...)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Add(%q) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListUnion(t *testing.T) {
+	a := List{"Action", "Drama"}
+	b := List{"drama", "Horror"}
+
+	got := a.Union(b)
+	if want := (List{"Action", "Drama", "Horror"}); !slices.Equal(got, want) {
+		t.Errorf("Union() = %q, want %q", got, want)
+	}
+	if !slices.Equal(a, List{"Action", "Drama"}) {
+		t.Errorf("Union() changed its receiver to %q", a)
+	}
+	if got := (List{}).Union(nil); len(got) != 0 {
+		t.Errorf("Union() of empty lists = %q", got)
+	}
+}
+
+func TestListXML(t *testing.T) {
+	type doc struct {
+		Genre List `xml:"Genre"`
+	}
+
+	var d doc
+	err := xml.Unmarshal([]byte(`<doc><Genre>action, Drama,,drama</Genre></doc>`), &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (List{"Action", "Drama"}); !slices.Equal(d.Genre, want) {
+		t.Errorf("decoded Genre = %q, want %q", d.Genre, want)
+	}
+
+	out, err := xml.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<doc><Genre>Action, Drama</Genre></doc>"; string(out) != want {
+		t.Errorf("encoded %s, want %s", out, want)
+	}
+}
@@ -0,0 +1,211 @@
+package standard
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MetronInfo https://metron-project.github.io/docs/category/metroninfo
+type MetronInfo struct {
+	XMLName   xml.Name                  `xml:"MetronInfo"`
+	XSI       string                    `xml:"xmlns:xsi,attr"`
+	Schema    string                    `xml:"xsi:noNamespaceSchemaLocation,attr"`
+	Publisher *MetronInfoPublisher      `xml:"Publisher,omitempty"`
+	Series    MetronInfoSeries          `xml:"Series"`
+	Number    string                    `xml:"Number,omitempty"`
+	Stories   xmlList[string]           `xml:"Stories"`
+	Summary   string                    `xml:"Summary,omitempty"`
+	Notes     string                    `xml:"Notes,omitempty"`
+	CoverDate string                    `xml:"CoverDate,omitempty"`
+	PageCount int                       `xml:"PageCount,omitempty"`
+	Genres    xmlList[string]           `xml:"Genres"`
+	Tags      xmlList[string]           `xml:"Tags"`
+	Arcs      xmlList[MetronInfoArc]    `xml:"Arcs"`
+	Chars     xmlList[string]           `xml:"Characters"`
+	Teams     xmlList[string]           `xml:"Teams"`
+	Locations xmlList[string]           `xml:"Locations"`
+	GTIN      *MetronInfoGTIN           `xml:"GTIN,omitempty"`
+	AgeRating string                    `xml:"AgeRating,omitempty"`
+	URLs      xmlList[MetronInfoURL]    `xml:"URLs"`
+	Credits   xmlList[MetronInfoCredit] `xml:"Credits"`
+	Pages     xmlList[ComicPageInfo]    `xml:"Pages"`
+}
+
+type MetronInfoPublisher struct {
+	Name    string `xml:"Name"`
+	Imprint string `xml:"Imprint,omitempty"`
+}
+
+type MetronInfoSeries struct {
+	Lang       string `xml:"lang,attr,omitempty"`
+	Name       string `xml:"Name"`
+	Volume     int    `xml:"Volume,omitempty"`
+	Format     string `xml:"Format,omitempty"`
+	IssueCount int    `xml:"IssueCount,omitempty"`
+}
+
+type MetronInfoArc struct {
+	Name   string `xml:"Name"`
+	Number string `xml:"Number,omitempty"`
+}
+
+type MetronInfoGTIN struct {
+	ISBN string `xml:"ISBN,omitempty"`
+	UPC  string `xml:"UPC,omitempty"`
+}
+
+type MetronInfoURL struct {
+	Primary bool   `xml:"primary,attr,omitempty"`
+	URL     string `xml:",chardata"`
+}
+
+type MetronInfoCredit struct {
+	Creator string          `xml:"Creator"`
+	Roles   xmlList[string] `xml:"Roles"`
+}
+
+// metronRoles maps ComicInfo creator fields to MetronInfo roles
+var metronRoles = map[string]string{
+	"Writer":      "Writer",
+	"Penciller":   "Penciller",
+	"Inker":       "Inker",
+	"Colorist":    "Colorist",
+	"Letterer":    "Letterer",
+	"CoverArtist": "Cover",
+	"Editor":      "Editor",
+	"Translator":  "Translator",
+}
+
+// metronFormats are the series formats the MetronInfo schema accepts
+var metronFormats = []string{
+	"Annual",
+	"Digital Chapter",
+	"Graphic Novel",
+	"Hardcover",
+	"Limited Series",
+	"Omnibus",
+	"One-Shot",
+	"Single Issue",
+	"Trade Paperback",
+}
+
+// metronAgeRatings maps ComicInfo age ratings to the MetronInfo enumeration
+var metronAgeRatings = map[string]string{
+	"Unknown":         "Unknown",
+	"Adults Only 18+": "Adult",
+	"Early Childhood": "Everyone",
+	"Everyone":        "Everyone",
+	"Everyone 10+":    "Everyone",
+	"G":               "Everyone",
+	"Kids to Adults":  "Everyone",
+	"M":               "Mature",
+	"MA15+":           "Mature",
+	"Mature 17+":      "Mature",
+	"PG":              "Teen",
+	"R18+":            "Adult",
+	"Rating Pending":  "Unknown",
+	"Teen":            "Teen",
+	"X18+":            "Explicit",
+}
+
+// NewMetronInfo converts ci to the MetronInfo model
+func NewMetronInfo(ci ComicInfoChapter) MetronInfo {
+	m := MetronInfo{
+		XSI:    "http://www.w3.org/2001/XMLSchema-instance",
+		Schema: "https://raw.githubusercontent.com/Metron-Project/metroninfo/master/schema/v1.0/MetronInfo.xsd",
+		Series: MetronInfoSeries{
+			Lang:       ci.LanguageISO,
+			Name:       ci.Series,
+			Volume:     ci.Volume,
+			IssueCount: ci.Count,
+		},
+		Number:    ci.Number,
+		Summary:   ci.Summary,
+		Notes:     ci.Notes,
+		PageCount: ci.PageCount,
+		Genres:    newXMLList("Genre", ci.Genre),
+		Tags:      newXMLList("Tag", ci.Tags),
+		Chars:     newXMLList("Character", ci.Characters),
+		Teams:     newXMLList("Team", ci.Teams),
+		Locations: newXMLList("Location", ci.Locations),
+		AgeRating: metronAgeRatings[ci.AgeRating],
+	}
+
+	if ci.Publisher != "" {
+		m.Publisher = &MetronInfoPublisher{
+			Name:    ci.Publisher,
+			Imprint: ci.Imprint,
+		}
+	}
+	for _, f := range metronFormats {
+		if strings.EqualFold(f, ci.Format) {
+			m.Series.Format = f
+		}
+	}
+	if ci.Title != "" {
+		m.Stories = newXMLList("Story", []string{ci.Title})
+	}
+	if ci.Year != 0 {
+		m.CoverDate = fmt.Sprintf(
+			"%04d-%02d-%02d",
+			ci.Year,
+			max(ci.Month, 1),
+			max(ci.Day, 1),
+		)
+	}
+
+	arcs := []MetronInfoArc{}
+	numbers := splitList(ci.StoryArcNumber)
+	for i, a := range splitList(ci.StoryArc) {
+		arc := MetronInfoArc{Name: a}
+		if i < len(numbers) {
+			arc.Number = numbers[i]
+		}
+		arcs = append(arcs, arc)
+	}
+	m.Arcs = newXMLList("Arc", arcs)
+
+	if ci.GTIN != "" {
+		// ISBN-13 starts with the 978/979 "Bookland" prefix
+		if strings.HasPrefix(ci.GTIN, "978") || strings.HasPrefix(ci.GTIN, "979") {
+			m.GTIN = &MetronInfoGTIN{ISBN: ci.GTIN}
+		} else {
+			m.GTIN = &MetronInfoGTIN{UPC: ci.GTIN}
+		}
+	}
+
+	urls := []MetronInfoURL{}
+	for i, u := range strings.Fields(ci.Web) {
+		urls = append(urls, MetronInfoURL{Primary: i == 0, URL: u})
+	}
+	m.URLs = newXMLList("URL", urls)
+
+	creds := []MetronInfoCredit{}
+	for _, c := range credits(ci) {
+		roles := make([]string, len(c.Roles))
+		for i, r := range c.Roles {
+			roles[i] = metronRoles[r]
+		}
+		creds = append(creds, MetronInfoCredit{
+			Creator: c.Person,
+			Roles:   newXMLList("Role", roles),
+		})
+	}
+	m.Credits = newXMLList("Credit", creds)
+	m.Pages = newXMLList("Page", []ComicPageInfo(ci.Pages))
+
+	return m
+}
+
+// MetronInfoExporter writes the Metron project's MetronInfo.xml
+type MetronInfoExporter struct{}
+
+func (MetronInfoExporter) Format() string      { return "metroninfo" }
+func (MetronInfoExporter) Filename() string    { return "MetronInfo.xml" }
+func (MetronInfoExporter) ContentType() string { return "application/xml" }
+
+func (MetronInfoExporter) Export(w io.Writer, ci ComicInfoChapter) error {
+	return encodeXML(w, NewMetronInfo(ci))
+}
@@ -0,0 +1,143 @@
+package standard
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/vyxn/yuzu/internal/pkg/yerr"
+)
+
+// Publication status of a series
+const (
+	SeriesStatusUnknown   = ""
+	SeriesStatusOngoing   = "Ongoing"
+	SeriesStatusEnded     = "Ended"
+	SeriesStatusHiatus    = "Hiatus"
+	SeriesStatusAbandoned = "Abandoned"
+)
+
+// ComicInfoSeries holds the metadata shared by every chapter of a series that
+// ComicInfoChapter has no place for
+type ComicInfoSeries struct {
+	Series          string  `json:"series,omitempty"`
+	AlternateSeries string  `json:"alternateSeries,omitempty"`
+	Summary         string  `json:"summary,omitempty"`
+	Status          string  `json:"status,omitempty"`
+	Year            int     `json:"year,omitempty"`
+	Count           int     `json:"count,omitempty"`
+	VolumeCount     int     `json:"volumeCount,omitempty"`
+	Publisher       string  `json:"publisher,omitempty"`
+	Imprint         string  `json:"imprint,omitempty"`
+	Genre           List    `json:"genre,omitempty"`
+	Tags            List    `json:"tags,omitempty"`
+	AgeRating       string  `json:"ageRating,omitempty"`
+	LanguageISO     string  `json:"languageISO,omitempty"`
+	Manga           string  `json:"manga,omitempty"`
+	CommunityRating float64 `json:"communityRating,omitempty"`
+	CoverURL        string  `json:"coverURL,omitempty"`
+	Web             string  `json:"web,omitempty"`
+	ProviderID      string  `json:"providerID,omitempty"`
+}
+
+// SeriesJSONFilename is the sidecar read by Mylar, Komga and Kavita
+const SeriesJSONFilename = "series.json"
+
+// SeriesJSON https://github.com/mylar3/mylar3/wiki/series.json-schema-(version-1.0.2)
+type SeriesJSON struct {
+	Version  string             `json:"version"`
+	Metadata SeriesJSONMetadata `json:"metadata"`
+}
+
+type SeriesJSONMetadata struct {
+	Type                 string `json:"type"`
+	Publisher            string `json:"publisher"`
+	Imprint              string `json:"imprint,omitempty"`
+	Name                 string `json:"name"`
+	ComicID              string `json:"comicid,omitempty"`
+	Year                 int    `json:"year,omitempty"`
+	DescriptionText      string `json:"description_text,omitempty"`
+	DescriptionFormatted string `json:"description_formatted,omitempty"`
+	Volume               int    `json:"volume,omitempty"`
+	BookType             string `json:"booktype"`
+	AgeRating            string `json:"age_rating,omitempty"`
+	ComicImage           string `json:"ComicImage,omitempty"`
+	TotalIssues          int    `json:"total_issues,omitempty"`
+	PublicationRun       string `json:"publication_run,omitempty"`
+	Status               string `json:"status"`
+}
+
+// NewSeriesJSON converts s to the Mylar series.json model
+func NewSeriesJSON(s ComicInfoSeries) SeriesJSON {
+	status := "Continuing"
+	if s.Status == SeriesStatusEnded || s.Status == SeriesStatusAbandoned {
+		status = "Ended"
+	}
+
+	return SeriesJSON{
+		Version: "1.0.2",
+		Metadata: SeriesJSONMetadata{
+			Type:            "comicSeries",
+			Publisher:       s.Publisher,
+			Imprint:         s.Imprint,
+			Name:            s.Series,
+			ComicID:         s.ProviderID,
+			Year:            s.Year,
+			DescriptionText: s.Summary,
+			BookType:        "Digital",
+			AgeRating:       s.AgeRating,
+			ComicImage:      s.CoverURL,
+			TotalIssues:     s.Count,
+			Status:          status,
+		},
+	}
+}
+
+// EncodeSeriesJSON writes s as a Mylar series.json
+func (s ComicInfoSeries) EncodeSeriesJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.SetEscapeHTML(false)
+
+	return e.Encode(NewSeriesJSON(s))
+}
+
+// ComicInfoSeries converts the Mylar model back, fields series.json has no
+// place for stay empty
+func (s SeriesJSON) ComicInfoSeries() ComicInfoSeries {
+	m := s.Metadata
+	status := SeriesStatusOngoing
+	if m.Status == "Ended" {
+		status = SeriesStatusEnded
+	}
+
+	return ComicInfoSeries{
+		Series:     m.Name,
+		Summary:    m.DescriptionText,
+		Status:     status,
+		Year:       m.Year,
+		Count:      m.TotalIssues,
+		Publisher:  m.Publisher,
+		Imprint:    m.Imprint,
+		AgeRating:  m.AgeRating,
+		CoverURL:   m.ComicImage,
+		ProviderID: m.ComicID,
+	}
+}
+
+// ReadSeriesJSON decodes a Mylar series.json stored on disk
+func ReadSeriesJSON(file string) (*ComicInfoSeries, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, yerr.WithStackf("opening <%s>: %w", file, err)
+	}
+	defer f.Close()
+
+	var s SeriesJSON
+	if err := json.NewDecoder(f).Decode(&s); err != nil {
+		return nil, yerr.WithStackf("decoding <%s>: %w", file, err)
+	}
+
+	cs := s.ComicInfoSeries()
+	return &cs, nil
+}
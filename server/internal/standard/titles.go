@@ -0,0 +1,148 @@
+package standard
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+)
+
+// Title is a series title in a given language, Lang is a BCP 47 tag where
+// romanizations use the Latn script, e.g. "ja-Latn" for romaji, and an empty
+// Lang means the language isn't known
+type Title struct {
+	Lang  string `json:"lang,omitempty"`
+	Value string `json:"value"`
+}
+
+// TitlePreference is the ordered list of languages titles are picked from
+type TitlePreference []string
+
+// DefaultTitlePreference prefers english, then romaji, then japanese
+var DefaultTitlePreference = TitlePreference{"en", "ja-Latn", "ja"}
+
+var titlePreference atomic.Pointer[TitlePreference]
+
+// ParseTitlePreference parses a comma separated list of language tags, it
+// returns DefaultTitlePreference when s holds none
+func ParseTitlePreference(s string) TitlePreference {
+	p := TitlePreference{}
+	for l := range strings.SplitSeq(s, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			p = append(p, l)
+		}
+	}
+	if len(p) == 0 {
+		return DefaultTitlePreference
+	}
+	return p
+}
+
+// SetTitlePreference changes the preference used by every provider
+func SetTitlePreference(p TitlePreference) {
+	titlePreference.Store(&p)
+}
+
+// GetTitlePreference returns the preference set with SetTitlePreference
+func GetTitlePreference() TitlePreference {
+	if p := titlePreference.Load(); p != nil {
+		return *p
+	}
+	return DefaultTitlePreference
+}
+
+// Prefers reports whether lang is the first choice of p
+func (p TitlePreference) Prefers(lang string) bool {
+	return len(p) > 0 && sameLanguage(p[0], lang)
+}
+
+// Select picks the preferred and the next best distinct title, titles in a
+// language outside of p are only used when nothing else is left and in the
+// order given, so providers should list their canonical title first
+func (p TitlePreference) Select(titles []Title) (preferred, alternate Title) {
+	picked := []Title{}
+	pick := func(t Title) {
+		if t.Value == "" || len(picked) == 2 {
+			return
+		}
+		for _, o := range picked {
+			if strings.EqualFold(o.Value, t.Value) {
+				return
+			}
+		}
+		picked = append(picked, t)
+	}
+
+	for _, lang := range p {
+		for _, t := range titles {
+			if sameLanguage(lang, t.Lang) {
+				pick(t)
+			}
+		}
+	}
+	for _, t := range titles {
+		pick(t)
+	}
+
+	if len(picked) > 0 {
+		preferred = picked[0]
+	}
+	if len(picked) > 1 {
+		alternate = picked[1]
+	}
+	return preferred, alternate
+}
+
+// Apply fills Series, AlternateSeries and LanguageISO of ci from titles
+func (p TitlePreference) Apply(ci *ComicInfoChapter, titles []Title) {
+	preferred, alternate := p.Select(titles)
+	ci.Series = preferred.Value
+	ci.AlternateSeries = alternate.Value
+	if lang := titleLanguage(preferred.Lang); lang != "" {
+		ci.LanguageISO = lang
+	}
+}
+
+// ApplySeries is Apply for series metadata
+func (p TitlePreference) ApplySeries(cs *ComicInfoSeries, titles []Title) {
+	preferred, alternate := p.Select(titles)
+	cs.Series = preferred.Value
+	cs.AlternateSeries = alternate.Value
+	if lang := titleLanguage(preferred.Lang); lang != "" {
+		cs.LanguageISO = lang
+	}
+}
+
+// sameLanguage compares the base language and script of two tags, so "en"
+// matches "en-US" and "en-Latn" while "ja" doesn't match "ja-Latn"
+func sameLanguage(a, b string) bool {
+	ta, err := language.Parse(a)
+	if err != nil || ta == language.Und {
+		return false
+	}
+	tb, err := language.Parse(b)
+	if err != nil || tb == language.Und {
+		return false
+	}
+
+	baseA, _ := ta.Base()
+	baseB, _ := tb.Base()
+	scriptA, _ := ta.Script()
+	scriptB, _ := tb.Script()
+	return baseA == baseB && scriptA == scriptB
+}
+
+// titleLanguage returns the language a title is written in, romanized titles
+// don't say anything about the language of the chapter so they return ""
+func titleLanguage(lang string) string {
+	tag, err := language.Parse(lang)
+	if err != nil || tag == language.Und {
+		return ""
+	}
+	base, _ := tag.Base()
+	if script, conf := tag.Script(); conf == language.Exact &&
+		script.String() == "Latn" && base.String() != "en" {
+		return ""
+	}
+	return base.String()
+}
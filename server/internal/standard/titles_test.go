@@ -0,0 +1,35 @@
+package standard
+
+import "testing"
+
+func TestSelect(t *testing.T) {
+	titles := []Title{
+		{Lang: "ja-Latn", Value: "Shingeki no Kyojin"},
+		{Lang: "en-US", Value: "Attack on Titan"},
+		{Lang: "ja", Value: "進撃の巨人"},
+	}
+
+	tests := []struct {
+		pref                 TitlePreference
+		preferred, alternate string
+	}{
+		{TitlePreference{"en", "ja-Latn"}, "Attack on Titan", "Shingeki no Kyojin"},
+		{TitlePreference{"en-Latn"}, "Attack on Titan", "Shingeki no Kyojin"},
+		{TitlePreference{"ja", "en"}, "進撃の巨人", "Attack on Titan"},
+		{TitlePreference{"ja-Latn", "ja"}, "Shingeki no Kyojin", "進撃の巨人"},
+		{TitlePreference{"fr"}, "Shingeki no Kyojin", "Attack on Titan"},
+	}
+	for _, tt := range tests {
+		preferred, alternate := tt.pref.Select(titles)
+		if preferred.Value != tt.preferred || alternate.Value != tt.alternate {
+			t.Errorf(
+				"%v.Select() = %q, %q, want %q, %q",
+				tt.pref,
+				preferred.Value,
+				alternate.Value,
+				tt.preferred,
+				tt.alternate,
+			)
+		}
+	}
+}
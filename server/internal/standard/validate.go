@@ -0,0 +1,319 @@
+package standard
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// ValidationMode decides what happens to values outside the ComicInfo schema
+type ValidationMode int
+
+const (
+	// ValidateStrict refuses to write a ComicInfo with invalid fields
+	ValidateStrict ValidationMode = iota
+	// ValidateRepair coerces invalid fields to the nearest valid value
+	ValidateRepair
+)
+
+// ParseValidationMode maps "strict" and "repair" to their mode, anything else
+// falls back to repair
+func ParseValidationMode(s string) ValidationMode {
+	if strings.EqualFold(s, "strict") {
+		return ValidateStrict
+	}
+	return ValidateRepair
+}
+
+// Enumerations from the Anansi v2.0/v2.1 ComicInfo.xsd
+var (
+	YesNoValues     = []string{"Unknown", "No", "Yes"}
+	MangaValues     = []string{"Unknown", "No", "Yes", "YesAndRightToLeft"}
+	AgeRatingValues = []string{
+		"Unknown",
+		"Adults Only 18+",
+		"Early Childhood",
+		"Everyone",
+		"Everyone 10+",
+		"G",
+		"Kids to Adults",
+		"M",
+		"MA15+",
+		"Mature 17+",
+		"PG",
+		"R18+",
+		"Rating Pending",
+		"Teen",
+		"X18+",
+	}
+	PageTypeValues = []string{
+		PageTypeFrontCover,
+		PageTypeInnerCover,
+		PageTypeRoundup,
+		PageTypeStory,
+		PageTypeAdvertisement,
+		PageTypeEditorial,
+		PageTypeLetters,
+		PageTypePreview,
+		PageTypeBackCover,
+		PageTypeOther,
+		PageTypeDeleted,
+	}
+)
+
+// ageRatingAliases maps ratings used by providers to the schema enumeration
+var ageRatingAliases = map[string]string{
+	"r":           "Mature 17+",
+	"r17":         "Mature 17+",
+	"r17+":        "Mature 17+",
+	"r18":         "R18+",
+	"pg13":        "Teen",
+	"pg-13":       "Teen",
+	"t":           "Teen",
+	"e":           "Everyone",
+	"e10+":        "Everyone 10+",
+	"ao":          "Adults Only 18+",
+	"x":           "X18+",
+	"18+":         "Adults Only 18+",
+	"mature":      "Mature 17+",
+	"adult":       "Adults Only 18+",
+	"all ages":    "Everyone",
+	"rp":          "Rating Pending",
+	"kids":        "Early Childhood",
+	"ec":          "Early Childhood",
+	"mature 17":   "Mature 17+",
+	"everyone 10": "Everyone 10+",
+}
+
+var yesNoAliases = map[string]string{
+	"true":  "Yes",
+	"y":     "Yes",
+	"1":     "Yes",
+	"false": "No",
+	"n":     "No",
+	"0":     "No",
+}
+
+var mangaAliases = map[string]string{
+	"true":              "Yes",
+	"y":                 "Yes",
+	"false":             "No",
+	"n":                 "No",
+	"rtl":               "YesAndRightToLeft",
+	"righttoleft":       "YesAndRightToLeft",
+	"yes and rtl":       "YesAndRightToLeft",
+	"yesandrtl":         "YesAndRightToLeft",
+	"yes right to left": "YesAndRightToLeft",
+}
+
+// FieldError describes a single field that doesn't satisfy the schema
+type FieldError struct {
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+	// Repaired holds the value the field was coerced to in repair mode
+	Repaired string `json:"repaired,omitempty"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s <%s>: %s", e.Field, e.Value, e.Message)
+}
+
+// ValidationError groups every FieldError found in a ComicInfoChapter
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "invalid comicinfo: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks c against the ComicInfo schema without modifying it and
+// returns a *ValidationError listing every invalid field
+func (c ComicInfoChapter) Validate() error {
+	errs := c.validate(false)
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: errs}
+}
+
+// Repair coerces every invalid field of c to the nearest valid value and
+// returns what was changed
+func (c *ComicInfoChapter) Repair() []FieldError {
+	return c.validate(true)
+}
+
+// Check applies mode to c, in strict mode it returns the validation error, in
+// repair mode it fixes c and never fails
+func (c *ComicInfoChapter) Check(mode ValidationMode) error {
+	if mode == ValidateRepair {
+		c.Repair()
+		return nil
+	}
+	return c.Validate()
+}
+
+func (c *ComicInfoChapter) validate(repair bool) []FieldError {
+	errs := []FieldError{}
+	add := func(field, value, msg string, fix func() string) {
+		fe := FieldError{Field: field, Value: value, Message: msg}
+		if repair {
+			fe.Repaired = fix()
+		}
+		errs = append(errs, fe)
+	}
+
+	if v, ok := checkEnum(c.Manga, MangaValues, mangaAliases); !ok {
+		add("Manga", c.Manga, "not a valid Manga value", func() string {
+			c.Manga = v
+			return v
+		})
+	}
+	if v, ok := checkEnum(c.BlackAndWhite, YesNoValues, yesNoAliases); !ok {
+		add("BlackAndWhite", c.BlackAndWhite, "not a valid YesNo value",
+			func() string {
+				c.BlackAndWhite = v
+				return v
+			})
+	}
+	if v, ok := checkEnum(c.AgeRating, AgeRatingValues, ageRatingAliases); !ok {
+		add("AgeRating", c.AgeRating, "not a valid AgeRating value",
+			func() string {
+				c.AgeRating = v
+				return v
+			})
+	}
+
+	if c.Year != 0 && (c.Year < 1000 || c.Year > 9999) {
+		add("Year", strconv.Itoa(c.Year), "must be a four digit year",
+			func() string {
+				c.Year = 0
+				return ""
+			})
+	}
+	if c.Month != 0 && (c.Month < 1 || c.Month > 12) {
+		add("Month", strconv.Itoa(c.Month), "must be between 1 and 12",
+			func() string {
+				c.Month = clamp(c.Month, 1, 12)
+				return strconv.Itoa(c.Month)
+			})
+	}
+	if c.Day != 0 && (c.Day < 1 || c.Day > 31) {
+		add("Day", strconv.Itoa(c.Day), "must be between 1 and 31",
+			func() string {
+				c.Day = clamp(c.Day, 1, 31)
+				return strconv.Itoa(c.Day)
+			})
+	}
+
+	rating := strconv.FormatFloat(c.CommunityRating, 'f', -1, 64)
+	if c.CommunityRating < 0 || c.CommunityRating > 5 ||
+		math.IsNaN(c.CommunityRating) {
+		add("CommunityRating", rating, "must be between 0 and 5",
+			func() string {
+				if math.IsNaN(c.CommunityRating) {
+					c.CommunityRating = 0
+				}
+				c.CommunityRating = roundRating(
+					math.Min(math.Max(c.CommunityRating, 0), 5),
+				)
+				return strconv.FormatFloat(c.CommunityRating, 'f', -1, 64)
+			})
+	} else if c.CommunityRating != roundRating(c.CommunityRating) {
+		add("CommunityRating", rating, "must have at most one decimal digit",
+			func() string {
+				c.CommunityRating = roundRating(c.CommunityRating)
+				return strconv.FormatFloat(c.CommunityRating, 'f', -1, 64)
+			})
+	}
+
+	if v, ok := checkLanguage(c.LanguageISO); !ok {
+		add("LanguageISO", c.LanguageISO, "not a valid language code",
+			func() string {
+				c.LanguageISO = v
+				return v
+			})
+	}
+
+	for i := range c.Pages {
+		p := &c.Pages[i]
+		field := fmt.Sprintf("Pages[%d].Type", i)
+		if p.Type != "" && !slices.Contains(PageTypeValues, p.Type) {
+			add(field, p.Type, "not a valid ComicPageType value",
+				func() string {
+					p.Type = nearestEnum(p.Type, PageTypeValues, nil, PageTypeOther)
+					return p.Type
+				})
+		}
+	}
+
+	return errs
+}
+
+// checkEnum reports whether v is a member of values, when it isn't it also
+// returns the nearest valid value
+func checkEnum(
+	v string,
+	values []string,
+	aliases map[string]string,
+) (string, bool) {
+	if v == "" || slices.Contains(values, v) {
+		return v, true
+	}
+	return nearestEnum(v, values, aliases, values[0]), false
+}
+
+func nearestEnum(
+	v string,
+	values []string,
+	aliases map[string]string,
+	fallback string,
+) string {
+	key := strings.ToLower(strings.TrimSpace(v))
+	for _, e := range values {
+		if strings.ToLower(e) == key {
+			return e
+		}
+	}
+	if a, ok := aliases[key]; ok {
+		return a
+	}
+	squashed := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(key)
+	for _, e := range values {
+		if strings.ToLower(strings.ReplaceAll(e, " ", "")) == squashed {
+			return e
+		}
+	}
+	return fallback
+}
+
+// checkLanguage reports whether v is a canonical language code, when it isn't
+// it also returns the canonical form or an empty string if it can't be parsed
+func checkLanguage(v string) (string, bool) {
+	if v == "" {
+		return v, true
+	}
+	tag, err := language.Parse(v)
+	if err != nil || tag == language.Und {
+		return "", false
+	}
+	canonical := tag.String()
+	return canonical, canonical == v
+}
+
+func clamp(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
+
+func roundRating(v float64) float64 {
+	return math.Round(v*10) / 10
+}
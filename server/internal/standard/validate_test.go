@@ -0,0 +1,184 @@
+package standard
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		name string
+		in   ComicInfoChapter
+		want ComicInfoChapter
+	}{
+		{
+			"valid values are kept",
+			ComicInfoChapter{
+				Manga:           "YesAndRightToLeft",
+				AgeRating:       "Teen",
+				Year:            1989,
+				Month:           12,
+				Day:             31,
+				CommunityRating: 4.5,
+				LanguageISO:     "en",
+			},
+			ComicInfoChapter{
+				Manga:           "YesAndRightToLeft",
+				AgeRating:       "Teen",
+				Year:            1989,
+				Month:           12,
+				Day:             31,
+				CommunityRating: 4.5,
+				LanguageISO:     "en",
+			},
+		},
+		{
+			"enum aliases",
+			ComicInfoChapter{Manga: "rtl", BlackAndWhite: "true", AgeRating: "R"},
+			ComicInfoChapter{
+				Manga:         "YesAndRightToLeft",
+				BlackAndWhite: "Yes",
+				AgeRating:     "Mature 17+",
+			},
+		},
+		{
+			"enum case and spacing",
+			ComicInfoChapter{Manga: "yes", AgeRating: "everyone-10+"},
+			ComicInfoChapter{Manga: "Yes", AgeRating: "Everyone 10+"},
+		},
+		{
+			"unknown enum values",
+			ComicInfoChapter{Manga: "sideways", AgeRating: "spicy"},
+			ComicInfoChapter{Manga: "Unknown", AgeRating: "Unknown"},
+		},
+		{
+			"dates out of range",
+			ComicInfoChapter{Year: 89, Month: 13, Day: -1},
+			ComicInfoChapter{Month: 12, Day: 1},
+		},
+		{
+			"rating above the range",
+			ComicInfoChapter{CommunityRating: 8.64},
+			ComicInfoChapter{CommunityRating: 5},
+		},
+		{
+			"rating below the range",
+			ComicInfoChapter{CommunityRating: -1},
+			ComicInfoChapter{CommunityRating: 0},
+		},
+		{
+			"rating rounded to one decimal",
+			ComicInfoChapter{CommunityRating: 4.3175},
+			ComicInfoChapter{CommunityRating: 4.3},
+		},
+		{
+			"rating rounded half up",
+			ComicInfoChapter{CommunityRating: 4.25},
+			ComicInfoChapter{CommunityRating: 4.3},
+		},
+		{
+			"language canonicalized",
+			ComicInfoChapter{LanguageISO: "EN-us"},
+			ComicInfoChapter{LanguageISO: "en-US"},
+		},
+		{
+			"three letter language",
+			ComicInfoChapter{LanguageISO: "jpn"},
+			ComicInfoChapter{LanguageISO: "ja"},
+		},
+		{
+			"unparsable language dropped",
+			ComicInfoChapter{LanguageISO: "not a language"},
+			ComicInfoChapter{},
+		},
+		{
+			"page types",
+			ComicInfoChapter{Pages: []ComicPageInfo{
+				{Type: PageTypeStory},
+				{Type: "backcover"},
+				{Type: "splash"},
+			}},
+			ComicInfoChapter{Pages: []ComicPageInfo{
+				{Type: PageTypeStory},
+				{Type: PageTypeBackCover},
+				{Type: PageTypeOther},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in
+			got.Pages = slices.Clone(tt.in.Pages)
+			fixed := got.Repair()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Repair() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("Validate() after Repair() = %v", err)
+			}
+			if changed := !reflect.DeepEqual(tt.in, tt.want); changed != (len(fixed) > 0) {
+				t.Errorf("Repair() reported %v for changed = %v", fixed, changed)
+			}
+		})
+	}
+}
+
+func TestValidateStrict(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     ComicInfoChapter
+		fields []string
+	}{
+		{"valid", ComicInfoChapter{Manga: "No", Year: 2000}, nil},
+		{"empty values are valid", ComicInfoChapter{}, nil},
+		{"alias", ComicInfoChapter{AgeRating: "R"}, []string{"AgeRating"}},
+		{"month", ComicInfoChapter{Month: 13}, []string{"Month"}},
+		{"rating edge", ComicInfoChapter{CommunityRating: 5}, nil},
+		{"rating decimals", ComicInfoChapter{CommunityRating: 4.35}, []string{"CommunityRating"}},
+		{"language case", ComicInfoChapter{LanguageISO: "EN"}, []string{"LanguageISO"}},
+		{
+			"several fields",
+			ComicInfoChapter{Manga: "rtl", Day: 32, LanguageISO: "xx-!!"},
+			[]string{"Manga", "Day", "LanguageISO"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ci := tt.in
+			err := ci.Check(ValidateStrict)
+			if !reflect.DeepEqual(ci, tt.in) {
+				t.Errorf("Check(ValidateStrict) modified the chapter to %+v", ci)
+			}
+
+			var fields []string
+			var verr *ValidationError
+			if errors.As(err, &verr) {
+				for _, fe := range verr.Errors {
+					fields = append(fields, fe.Field)
+				}
+			} else if err != nil {
+				t.Fatalf("Check(ValidateStrict) = %v, want a *ValidationError", err)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("invalid fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestParseValidationMode(t *testing.T) {
+	tests := map[string]ValidationMode{
+		"strict":  ValidateStrict,
+		"STRICT":  ValidateStrict,
+		"repair":  ValidateRepair,
+		"":        ValidateRepair,
+		"lenient": ValidateRepair,
+	}
+	for in, want := range tests {
+		if got := ParseValidationMode(in); got != want {
+			t.Errorf("ParseValidationMode(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -48,7 +48,7 @@ func main() {
 
 	internal.SetupMiddleware(e)
 	internal.SetupErrorHandling(e)
-	internal.SetupRoutes(e)
+	internal.SetupRoutes(e, db)
 
 	port := ":8080"
 	logger.Info("http server started", slog.String("port", port))
@@ -0,0 +1,2 @@
+-- Create "override" table
+CREATE TABLE `override` (`series` text NOT NULL, `chapter` text NOT NULL DEFAULT '', `fields` json NOT NULL DEFAULT '{}', `locked` json NOT NULL DEFAULT '[]', `updated_at` datetime NOT NULL DEFAULT (CURRENT_TIMESTAMP), PRIMARY KEY (`series`, `chapter`));
@@ -0,0 +1,4 @@
+-- Create "cache" table
+CREATE TABLE `cache` (`provider` text NOT NULL, `key` text NOT NULL, `value` blob NOT NULL, `size` integer NOT NULL, `created_at` datetime NOT NULL DEFAULT (CURRENT_TIMESTAMP), `expires_at` datetime NOT NULL, PRIMARY KEY (`provider`, `key`));
+-- Create index "cache_expires_at" to table: "cache"
+CREATE INDEX `cache_expires_at` ON `cache` (`expires_at`);
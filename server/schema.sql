@@ -1,3 +1,24 @@
 CREATE TABLE "config" (
   "config" json NOT NULL DEFAULT '{}'
 );
+
+CREATE TABLE "override" (
+  "series" text NOT NULL,
+  "chapter" text NOT NULL DEFAULT '',
+  "fields" json NOT NULL DEFAULT '{}',
+  "locked" json NOT NULL DEFAULT '[]',
+  "updated_at" datetime NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  PRIMARY KEY ("series", "chapter")
+);
+
+CREATE TABLE "cache" (
+  "provider" text NOT NULL,
+  "key" text NOT NULL,
+  "value" blob NOT NULL,
+  "size" integer NOT NULL,
+  "created_at" datetime NOT NULL DEFAULT (CURRENT_TIMESTAMP),
+  "expires_at" datetime NOT NULL,
+  PRIMARY KEY ("provider", "key")
+);
+
+CREATE INDEX "cache_expires_at" ON "cache" ("expires_at");